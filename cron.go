@@ -7,14 +7,19 @@ import (
 	"github.com/robfig/cron/v3"
 )
 
-func NewScheduller() *cron.Cron {
-	jakartaTime, err := time.LoadLocation("Asia/Jakarta")
+// loadTimeZone returns the named time zone, or UTC if it can't be loaded.
+func loadTimeZone(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
 	if err != nil {
-		fmt.Println("Failed to use Jakarta time. Using UTC instead.")
-		jakartaTime = time.UTC
+		fmt.Printf("Failed to use time zone %s. Using UTC instead.\n", name)
+		return time.UTC
 	}
-	fmt.Println("Using time zone:", jakartaTime)
-	scheduller := cron.New(cron.WithLocation(jakartaTime))
+	return loc
+}
+
+func NewScheduller(loc *time.Location) *cron.Cron {
+	fmt.Println("Using time zone:", loc)
+	scheduller := cron.New(cron.WithLocation(loc))
 
 	return scheduller
 }
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"tinyurl/internal/ratelimit"
+	"tinyurl/internal/service"
+	pb "tinyurl/proto/tinyurl/v1"
+)
+
+// EnumerationGuard blocks clients that hit too many unknown short codes,
+// which is what scanning the code space looks like. Blocked clients are
+// tarpitted before they get their answer, so scanning becomes slow too.
+type EnumerationGuard struct {
+	rdb     *redis.Client
+	limiter *ratelimit.Limiter
+
+	Misses   ratelimit.Limit // allowed 404s per client
+	BlockFor time.Duration
+	Tarpit   time.Duration
+}
+
+func NewEnumerationGuard(rdb *redis.Client, limiter *ratelimit.Limiter) *EnumerationGuard {
+	return &EnumerationGuard{
+		rdb:      rdb,
+		limiter:  limiter,
+		Misses:   ratelimit.Limit{Rate: 20, Window: time.Minute},
+		BlockFor: 10 * time.Minute,
+		Tarpit:   2 * time.Second,
+	}
+}
+
+// Blocked reports whether ip is blocked and for how much longer.
+func (g *EnumerationGuard) Blocked(ctx context.Context, ip string) (time.Duration, bool) {
+	ttl, err := g.rdb.TTL(ctx, "redirect_block:"+ip).Result()
+	if err != nil || ttl <= 0 {
+		return 0, false
+	}
+	return ttl, true
+}
+
+// RecordMiss counts a 404 for ip and blocks it once it exceeds the limit.
+func (g *EnumerationGuard) RecordMiss(ctx context.Context, ip string) {
+	if res := g.limiter.Allow(ctx, "redirect_miss:"+ip, g.Misses); res.Allowed {
+		return
+	}
+
+	fmt.Printf("Blocking %s for %s after too many unknown short codes\n", ip, g.BlockFor)
+	if err := g.rdb.Set(ctx, "redirect_block:"+ip, 1, g.BlockFor).Err(); err != nil {
+		fmt.Println("Failed to block client:", err)
+	}
+}
+
+// GuardedMethods are the RPCs that look up a short code for anyone, so
+// scanners can use each of them to find out which codes exist.
+var GuardedMethods = map[string]bool{
+	pb.TinyURL_GetOriginal_FullMethodName: true,
+	pb.TinyURL_PreviewLink_FullMethodName: true,
+	pb.TinyURL_UnlockLink_FullMethodName:  true,
+}
+
+// UnaryServerInterceptor applies the guard to GuardedMethods, so lookups
+// through the gateway and direct gRPC count the same as redirects.
+func (g *EnumerationGuard) UnaryServerInterceptor(ipFromContext func(context.Context) string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !GuardedMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		ip := ipFromContext(ctx)
+		if remaining, blocked := g.Blocked(ctx, ip); blocked {
+			g.Wait(ctx)
+			grpc.SetHeader(ctx, metadata.Pairs(ratelimit.HeaderRetryAfter, strconv.Itoa(int(remaining.Seconds()))))
+			return nil, status.Error(codes.ResourceExhausted, "Too many unknown links. Try again later.")
+		}
+
+		resp, err := handler(ctx, req)
+		// Expired links are NotFound too, but aren't a sign of guessing
+		if status.Code(err) == codes.NotFound && service.ErrorInfo(err) == nil {
+			g.RecordMiss(ctx, ip)
+		}
+		return resp, err
+	}
+}
+
+// Wait holds a blocked client for the tarpit delay, or until it gives up.
+func (g *EnumerationGuard) Wait(ctx context.Context) {
+	select {
+	case <-time.After(g.Tarpit):
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"tinyurl/internal/service"
+)
+
+// UnavailableRetryAfter is sent with 503 responses, in seconds.
+var UnavailableRetryAfter = "30"
+
+// errorPages are the titles and texts shown to browsers per HTTP status.
+var errorPages = map[int][2]string{
+	http.StatusBadRequest:          {"Invalid link", "This doesn't look like a valid short link."},
+	http.StatusForbidden:           {"Link blocked", "This link has been blocked."},
+	http.StatusNotFound:            {"Link not found", "We couldn't find this link. Check it for typos."},
+	http.StatusGone:                {"Link gone", "This link is no longer available."},
+	http.StatusTooManyRequests:     {"Slow down", "You're making too many requests. Please wait a moment and try again."},
+	http.StatusInternalServerError: {"Something went wrong", "Something went wrong on our side. Please try again later."},
+	http.StatusServiceUnavailable:  {"Temporarily unavailable", "We can't open this link right now. Please try again in a moment."},
+	http.StatusGatewayTimeout:      {"Temporarily unavailable", "We can't open this link right now. Please try again in a moment."},
+}
+
+// httpStatus maps a GetOriginal error to the HTTP status of the redirect.
+func httpStatus(err error) int {
+	if info := service.ErrorInfo(err); info != nil {
+		switch info.Reason {
+		case service.ReasonLinkDisabled:
+			if info.Metadata["legal"] == "true" {
+				return http.StatusUnavailableForLegalReasons
+			}
+			return http.StatusGone
+		case service.ReasonLinkExpired:
+			return http.StatusGone
+		case service.ReasonLinkNotActive:
+			return http.StatusForbidden
+		}
+	}
+
+	switch status.Code(err) {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.FailedPrecondition:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeError answers a failed redirect with the matching HTTP status, as
+// JSON for API clients and as a branded page for browsers.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	code := httpStatus(err)
+	st := status.Convert(err)
+	if code >= 500 {
+		fmt.Printf("Redirect error for %s: %v\n", r.URL.Path, err)
+		// Don't leak internals
+		st = status.New(st.Code(), errorPages[code][1])
+	}
+	if code == http.StatusServiceUnavailable && w.Header().Get("Retry-After") == "" {
+		w.Header().Set("Retry-After", UnavailableRetryAfter)
+	}
+
+	if wantsJSON(r) {
+		data, err := protojson.Marshal(st.Proto())
+		if err != nil {
+			http.Error(w, st.Message(), code)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		w.Write(data)
+		return
+	}
+
+	switch info := service.ErrorInfo(err); {
+	case info == nil:
+	case info.Reason == service.ReasonLinkDisabled:
+		renderPage(w, code, "disabled.html", map[string]string{
+			"Title":  "Link disabled",
+			"Reason": info.Metadata["reason"],
+			"Legal":  info.Metadata["legal"],
+		})
+		return
+	case info.Reason == service.ReasonAccessDenied:
+		renderPage(w, code, "error.html", map[string]any{
+			"Title":   "Access restricted",
+			"Message": "This link is only available from certain networks or websites.",
+			"Code":    code,
+		})
+		return
+	case info.Reason == service.ReasonLinkNotActive:
+		activeAt, _ := time.Parse(time.RFC3339, info.Metadata["active_at"])
+		if loc, err := time.LoadLocation(info.Metadata["time_zone"]); err == nil {
+			activeAt = activeAt.In(loc)
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		renderPage(w, code, "countdown.html", map[string]any{
+			"Title":    "Coming soon",
+			"ActiveAt": activeAt,
+		})
+		return
+	case info.Reason == service.ReasonLinkExpired:
+		expiredAt, _ := time.Parse(time.RFC3339, info.Metadata["expired_at"])
+		renderPage(w, code, "expired.html", map[string]any{
+			"Title":     "Link expired",
+			"ExpiredAt": expiredAt,
+			"UsedUp":    info.Metadata["reason"] == service.ExpiredReasonMaxClicks,
+		})
+		return
+	}
+
+	page, ok := errorPages[code]
+	if !ok {
+		page = errorPages[http.StatusInternalServerError]
+	}
+	message := page[1]
+	if code == http.StatusBadRequest || code == http.StatusTooManyRequests {
+		message = st.Message()
+	}
+	renderPage(w, code, "error.html", map[string]any{
+		"Title":   page[0],
+		"Message": message,
+		"Code":    code,
+	})
+}
+
+// wantsJSON reports whether the client asked for JSON rather than HTML.
+func wantsJSON(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case "application/json":
+			return true
+		case "text/html":
+			return false
+		}
+	}
+	return false
+}
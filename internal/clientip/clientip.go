@@ -0,0 +1,179 @@
+package clientip
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// DefaultTrustedProxies only trusts loopback, which covers the in-process
+// gateway and redirect handler talking to the gRPC server.
+var DefaultTrustedProxies = []string{"127.0.0.0/8", "::1/128"}
+
+// The forwarding headers a trusted proxy may set.
+const (
+	HeaderXForwardedFor = "X-Forwarded-For"
+	HeaderForwarded     = "Forwarded"
+)
+
+// Resolver finds the real client IP of a request. Forwarding headers are only
+// believed when they were added by a trusted proxy, and are read right to
+// left so a client can't spoof its address by prepending entries.
+type Resolver struct {
+	trusted []netip.Prefix
+	// header is the only forwarding header read. A proxy appends to one of
+	// them and passes the other through untouched, so reading both would
+	// let clients pick their own address.
+	header string
+}
+
+// NewResolver parses a list of trusted proxy CIDRs. Plain IPs are accepted as
+// single host prefixes. header is the forwarding header the proxies set,
+// HeaderXForwardedFor when empty.
+func NewResolver(cidrs []string, header string) (*Resolver, error) {
+	r := &Resolver{header: HeaderXForwardedFor}
+	switch {
+	case header == "":
+	case strings.EqualFold(header, HeaderXForwardedFor):
+	case strings.EqualFold(header, HeaderForwarded):
+		r.header = HeaderForwarded
+	default:
+		return nil, fmt.Errorf("unknown forwarding header %q, expected %s or %s", header, HeaderXForwardedFor, HeaderForwarded)
+	}
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		prefix, err := ParsePrefix(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q: %w", cidr, err)
+		}
+		r.trusted = append(r.trusted, prefix)
+	}
+	return r, nil
+}
+
+// ParsePrefix parses a CIDR or a single IP address.
+func ParsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+func (r *Resolver) Trusted(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range r.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// FromRequest returns the client IP of an HTTP request, read from the
+// forwarding header the resolver was configured with.
+func (r *Resolver) FromRequest(req *http.Request) string {
+	var chain []string
+	if r.header == HeaderForwarded {
+		chain = parseForwarded(req.Header.Values(HeaderForwarded))
+	} else {
+		chain = splitList(req.Header.Values(HeaderXForwardedFor))
+	}
+	return r.resolve(stripPort(req.RemoteAddr), chain)
+}
+
+// FromContext returns the client IP of a gRPC call, using the peer address
+// and the x-forwarded-for metadata set by the gateway and redirect handler.
+func (r *Resolver) FromContext(ctx context.Context) string {
+	var remote string
+	if p, ok := peer.FromContext(ctx); ok {
+		remote = stripPort(p.Addr.String())
+	}
+
+	var chain []string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		chain = splitList(md.Get("x-forwarded-for"))
+	}
+	return r.resolve(remote, chain)
+}
+
+// resolve walks the forwarding chain from the closest hop outwards and
+// returns the first address that is not a trusted proxy. A hop that isn't
+// an IP, like "unknown" or an obfuscated "_node", can't be told apart from
+// other clients, so the trusted proxy that reported it is returned instead.
+// A chain made only of trusted hops resolves to its first entry.
+func (r *Resolver) resolve(remote string, chain []string) string {
+	client := remote
+	for i := len(chain) - 1; i >= 0 && r.Trusted(client); i-- {
+		if _, err := netip.ParseAddr(chain[i]); err != nil {
+			break
+		}
+		client = chain[i]
+	}
+	return client
+}
+
+// parseForwarded extracts the "for" parameters of RFC 7239 Forwarded headers
+// in order, e.g. `for=192.0.2.60;proto=http, for="[2001:db8::17]:4711"`.
+func parseForwarded(values []string) []string {
+	var chain []string
+	for _, value := range values {
+		for _, element := range strings.Split(value, ",") {
+			for _, pair := range strings.Split(element, ";") {
+				name, val, ok := strings.Cut(strings.TrimSpace(pair), "=")
+				if !ok || !strings.EqualFold(name, "for") {
+					continue
+				}
+				val = strings.Trim(val, `"`)
+				chain = append(chain, stripPort(val))
+			}
+		}
+	}
+	return chain
+}
+
+func splitList(values []string) []string {
+	var list []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, stripPort(item))
+			}
+		}
+	}
+	return list
+}
+
+// stripPort removes the port from "ip:port" and "[ipv6]:port" forms and
+// normalizes the address, so one client always maps to the same string.
+func stripPort(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	addr = strings.TrimSuffix(strings.TrimPrefix(addr, "["), "]")
+	if ip, err := netip.ParseAddr(addr); err == nil {
+		return ip.Unmap().String()
+	}
+	return addr
+}
@@ -0,0 +1,110 @@
+package clientip
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func newTestResolver(t *testing.T, header string) *Resolver {
+	t.Helper()
+	r, err := NewResolver([]string{"127.0.0.0/8", "10.0.0.0/24", "2001:db8:ffff::/48"}, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestResolve(t *testing.T) {
+	r := newTestResolver(t, "")
+	tests := []struct {
+		name   string
+		remote string
+		chain  []string
+		want   string
+	}{
+		{"no chain", "198.51.100.7", nil, "198.51.100.7"},
+		{"untrusted remote ignores chain", "198.51.100.7", []string{"10.8.0.1"}, "198.51.100.7"},
+		{"one trusted hop", "10.0.0.5", []string{"198.51.100.7"}, "198.51.100.7"},
+		{"spoofed hop prepended by client", "10.0.0.5", []string{"10.8.0.1", "198.51.100.7"}, "198.51.100.7"},
+		{"spoofed trusted hop", "10.0.0.5", []string{"127.0.0.1", "198.51.100.7"}, "198.51.100.7"},
+		{"two trusted hops", "127.0.0.1", []string{"198.51.100.7", "10.0.0.9"}, "198.51.100.7"},
+		{"only trusted hops", "127.0.0.1", []string{"10.0.0.8", "10.0.0.9"}, "10.0.0.8"},
+		{"ipv6 client", "10.0.0.5", []string{"2001:db8::17"}, "2001:db8::17"},
+		{"ipv6 trusted proxy", "2001:db8:ffff::1", []string{"2001:db8::17"}, "2001:db8::17"},
+		{"unknown hop", "10.0.0.5", []string{"198.51.100.7", "unknown"}, "10.0.0.5"},
+		{"obfuscated hop", "127.0.0.1", []string{"_hidden", "10.0.0.9"}, "10.0.0.9"},
+		{"garbage hop", "10.0.0.5", []string{"not an ip"}, "10.0.0.5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.resolve(tt.remote, tt.chain); got != tt.want {
+				t.Errorf("resolve(%q, %q) = %q, want %q", tt.remote, tt.chain, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseForwarded(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   []string
+	}{
+		{"single", []string{"for=192.0.2.60"}, []string{"192.0.2.60"}},
+		{"with other params", []string{"for=192.0.2.60;proto=http;by=203.0.113.43"}, []string{"192.0.2.60"}},
+		{"case insensitive name", []string{"For=192.0.2.60"}, []string{"192.0.2.60"}},
+		{"list", []string{"for=192.0.2.43, for=198.51.100.17"}, []string{"192.0.2.43", "198.51.100.17"}},
+		{"several headers", []string{"for=192.0.2.43", "for=198.51.100.17"}, []string{"192.0.2.43", "198.51.100.17"}},
+		{"quoted ipv4 with port", []string{`for="192.0.2.43:4711"`}, []string{"192.0.2.43"}},
+		{"ipv6 with brackets and port", []string{`for="[2001:db8:cafe::17]:4711"`}, []string{"2001:db8:cafe::17"}},
+		{"ipv6 with brackets", []string{`for="[2001:db8:cafe::17]"`}, []string{"2001:db8:cafe::17"}},
+		{"ipv6 with obfuscated port", []string{`for="[2001:db8:cafe::17]:_port"`}, []string{"2001:db8:cafe::17"}},
+		{"ipv4 mapped ipv6", []string{`for="[::ffff:192.0.2.1]"`}, []string{"192.0.2.1"}},
+		{"unknown", []string{"for=unknown, for=198.51.100.17"}, []string{"unknown", "198.51.100.17"}},
+		{"obfuscated", []string{"for=_hidden, for=_SEVKISEK"}, []string{"_hidden", "_SEVKISEK"}},
+		{"no for", []string{"proto=https;by=203.0.113.43"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseForwarded(tt.values); !slices.Equal(got, tt.want) {
+				t.Errorf("parseForwarded(%q) = %q, want %q", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromRequestReadsOneHeader(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{HeaderXForwardedFor, "198.51.100.7"},
+		{HeaderForwarded, "10.8.0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			r := newTestResolver(t, tt.header)
+			req, _ := http.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = "10.0.0.5:52000"
+			req.Header.Set("X-Forwarded-For", "198.51.100.7")
+			req.Header.Set("Forwarded", "for=10.8.0.1")
+			if got := r.FromRequest(req); got != tt.want {
+				t.Errorf("FromRequest() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewResolverRejectsUnknownHeader(t *testing.T) {
+	if _, err := NewResolver(nil, "X-Real-IP"); err == nil {
+		t.Error("NewResolver accepted X-Real-IP")
+	}
+	r, err := NewResolver(nil, "forwarded")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.header != HeaderForwarded {
+		t.Errorf("header = %q, want %q", r.header, HeaderForwarded)
+	}
+}
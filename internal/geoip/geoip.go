@@ -0,0 +1,65 @@
+package geoip
+
+import (
+	"net"
+	"strings"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// DB looks up the country of an IP address in an offline MaxMind database
+// (GeoLite2-Country, GeoIP2-Country or anything with the same layout).
+// A nil DB knows no countries.
+type DB struct {
+	reader *maxminddb.Reader
+}
+
+type record struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	RegisteredCountry struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"registered_country"`
+}
+
+// Open loads the database at path.
+func Open(path string) (*DB, error) {
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &DB{reader: reader}, nil
+}
+
+// Country returns the upper case ISO 3166-1 code of the country ip is in,
+// or "" if it isn't known.
+func (db *DB) Country(ip string) string {
+	if db == nil {
+		return ""
+	}
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return ""
+	}
+
+	var rec record
+	if err := db.reader.Lookup(addr, &rec); err != nil {
+		return ""
+	}
+	if rec.Country.ISOCode != "" {
+		return strings.ToUpper(rec.Country.ISOCode)
+	}
+	return strings.ToUpper(rec.RegisteredCountry.ISOCode)
+}
+
+// Groups are names for sets of countries that can be used in place of a
+// country code.
+var Groups = map[string][]string{
+	// Member states of the European Union
+	"EU": {"AT", "BE", "BG", "CY", "CZ", "DE", "DK", "EE", "ES", "FI", "FR", "GR", "HR", "HU", "IE", "IT", "LT", "LU", "LV", "MT", "NL", "PL", "PT", "RO", "SE", "SI", "SK"},
+	// European Economic Area, the EU plus Iceland, Liechtenstein and Norway
+	"EEA": {"AT", "BE", "BG", "CY", "CZ", "DE", "DK", "EE", "ES", "FI", "FR", "GR", "HR", "HU", "IE", "IT", "LT", "LU", "LV", "MT", "NL", "PL", "PT", "RO", "SE", "SI", "SK", "IS", "LI", "NO"},
+	// Member states of ASEAN
+	"ASEAN": {"BN", "KH", "ID", "LA", "MY", "MM", "PH", "SG", "TH", "VN"},
+}
@@ -0,0 +1,217 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ErrBlockedAddress is returned for destinations on loopback, private,
+// link-local or unspecified addresses. Anyone can shorten a URL, so the
+// checker must not become a way to probe our own network.
+var ErrBlockedAddress = errors.New("destination is not a public address")
+
+// MaxRedirects is how many redirects a check follows before giving up.
+const MaxRedirects = 5
+
+// Result of checking a single destination.
+type Result struct {
+	Status    int // HTTP status, 0 when the request failed
+	Error     string
+	CheckedAt time.Time
+}
+
+// OK reports whether the destination looks alive. Login walls and rate
+// limits count as alive, only missing pages, server errors and network
+// failures don't.
+func (r Result) OK() bool {
+	if r.Error != "" {
+		return false
+	}
+	return r.Status < 500 && r.Status != http.StatusNotFound && r.Status != http.StatusGone
+}
+
+// Checker probes destination URLs. Hosts are checked in parallel, but the
+// URLs of a single host one at a time with HostDelay in between, so a
+// popular destination doesn't get hammered.
+type Checker struct {
+	Client      *http.Client
+	Concurrency int           // Hosts checked in parallel
+	Timeout     time.Duration // Per request
+	HostDelay   time.Duration // Between requests to the same host
+	UserAgent   string
+}
+
+// NewChecker returns a checker that only connects to public addresses. The
+// address is checked after DNS resolution, on every connection, so neither
+// a hostname nor a redirect can point it somewhere else.
+func NewChecker() *Checker {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: dialControl,
+	}
+	return &Checker{
+		Client: &http.Client{
+			// No proxy from the environment, it would be dialed instead of
+			// the destination
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: 10 * time.Second,
+				MaxIdleConnsPerHost: 2,
+			},
+			CheckRedirect: checkRedirect,
+		},
+		Concurrency: 16,
+		Timeout:     10 * time.Second,
+		HostDelay:   time.Second,
+		UserAgent:   "TinyURL-HealthCheck/1.0",
+	}
+}
+
+// Check probes rawURL with HEAD, falling back to GET for servers that
+// reject or fail HEAD requests.
+func (c *Checker) Check(ctx context.Context, rawURL string) Result {
+	status, err := c.do(ctx, http.MethodHead, rawURL)
+	if err != nil || status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented || status >= 500 {
+		status, err = c.do(ctx, http.MethodGet, rawURL)
+	}
+
+	r := Result{Status: status, CheckedAt: time.Now()}
+	if err != nil {
+		r.Error = err.Error()
+	}
+	return r
+}
+
+func (c *Checker) do(ctx context.Context, method, rawURL string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", c.UserAgent)
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drain a little so the connection can be reused
+	io.CopyN(io.Discard, resp.Body, 64<<10)
+	return resp.StatusCode, nil
+}
+
+func dialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if blocked(addr) {
+		return ErrBlockedAddress
+	}
+	return nil
+}
+
+// reservedPrefixes are the special purpose ranges (RFC 6890) the netip
+// predicates don't cover. None of them belong to a public website, and some,
+// like shared address space, reach into a provider's network.
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "this" network
+	netip.MustParsePrefix("100.64.0.0/10"),   // shared address space (CGNAT)
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // documentation
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // documentation
+	netip.MustParsePrefix("203.0.113.0/24"),  // documentation
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved, and broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64, may map to any IPv4
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // local NAT64
+	netip.MustParsePrefix("100::/64"),        // discard
+	netip.MustParsePrefix("2001:db8::/32"),   // documentation
+}
+
+func blocked(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsMulticast() {
+		return true
+	}
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRedirect follows a few redirects to http and https URLs only. Where
+// they lead is checked when dialing, like the destination itself.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= MaxRedirects {
+		return fmt.Errorf("stopped after %d redirects", MaxRedirects)
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return fmt.Errorf("redirect to unsupported scheme %q", req.URL.Scheme)
+	}
+	return nil
+}
+
+// CheckAll checks every URL once and calls fn with each result as soon as
+// it's known. fn is called from several goroutines at once.
+func (c *Checker) CheckAll(ctx context.Context, urls []string, fn func(rawURL string, r Result)) {
+	byHost := map[string][]string{}
+	for _, u := range urls {
+		parsed, err := url.Parse(u)
+		if err != nil {
+			fn(u, Result{Error: fmt.Sprintf("invalid URL: %v", err), CheckedAt: time.Now()})
+			continue
+		}
+		byHost[parsed.Host] = append(byHost[parsed.Host], u)
+	}
+
+	hosts := make(chan []string)
+	var wg sync.WaitGroup
+	for range max(c.Concurrency, 1) {
+		wg.Go(func() {
+			for urls := range hosts {
+				c.checkHost(ctx, urls, fn)
+			}
+		})
+	}
+
+	for _, urls := range byHost {
+		select {
+		case hosts <- urls:
+		case <-ctx.Done():
+		}
+	}
+	close(hosts)
+	wg.Wait()
+}
+
+func (c *Checker) checkHost(ctx context.Context, urls []string, fn func(string, Result)) {
+	for i, u := range urls {
+		if i > 0 {
+			select {
+			case <-time.After(c.HostDelay):
+			case <-ctx.Done():
+				return
+			}
+		}
+		fn(u, c.Check(ctx, u))
+	}
+}
@@ -0,0 +1,205 @@
+package health
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// newTestChecker talks to httptest servers, which listen on loopback and
+// would be refused by NewChecker's client.
+func newTestChecker(srv *httptest.Server) *Checker {
+	c := NewChecker()
+	c.Client = srv.Client()
+	c.Timeout = time.Second
+	c.HostDelay = 0
+	return c
+}
+
+func TestCheckHead(t *testing.T) {
+	var gets atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			gets.Add(1)
+		}
+	}))
+	defer srv.Close()
+
+	r := newTestChecker(srv).Check(context.Background(), srv.URL)
+	if !r.OK() || r.Status != http.StatusOK {
+		t.Errorf("Check() = %+v, want 200", r)
+	}
+	if gets.Load() != 0 {
+		t.Errorf("sent %d GET requests after a successful HEAD", gets.Load())
+	}
+}
+
+func TestCheckFallsBackToGet(t *testing.T) {
+	tests := []struct {
+		name       string
+		headStatus int
+		getStatus  int
+		want       int
+		wantOK     bool
+	}{
+		{"method not allowed", http.StatusMethodNotAllowed, http.StatusOK, http.StatusOK, true},
+		{"not implemented", http.StatusNotImplemented, http.StatusOK, http.StatusOK, true},
+		{"server error", http.StatusInternalServerError, http.StatusOK, http.StatusOK, true},
+		{"still failing", http.StatusMethodNotAllowed, http.StatusBadGateway, http.StatusBadGateway, false},
+		{"not found", http.StatusNotFound, http.StatusOK, http.StatusNotFound, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodHead {
+					w.WriteHeader(tt.headStatus)
+					return
+				}
+				w.WriteHeader(tt.getStatus)
+			}))
+			defer srv.Close()
+
+			r := newTestChecker(srv).Check(context.Background(), srv.URL)
+			if r.Status != tt.want || r.OK() != tt.wantOK {
+				t.Errorf("Check() = %+v, want status %d ok %v", r, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestCheckNetworkError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	c := newTestChecker(srv)
+	srv.Close()
+
+	r := c.Check(context.Background(), srv.URL)
+	if r.OK() || r.Status != 0 || r.Error == "" {
+		t.Errorf("Check() of a closed server = %+v, want an error", r)
+	}
+}
+
+func TestCheckBlockedAddress(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+
+	c := NewChecker()
+	c.Timeout = time.Second
+	for _, rawURL := range []string{
+		srv.URL,
+		"http://localhost:" + u.Port(),
+		"http://[::1]:" + u.Port(),
+	} {
+		r := c.Check(context.Background(), rawURL)
+		if r.OK() || !strings.Contains(r.Error, ErrBlockedAddress.Error()) {
+			t.Errorf("Check(%q) = %+v, want %v", rawURL, r, ErrBlockedAddress)
+		}
+	}
+	if hits.Load() != 0 {
+		t.Errorf("server got %d requests", hits.Load())
+	}
+}
+
+func TestCheckRedirectToBlockedAddress(t *testing.T) {
+	var hits atomic.Int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer target.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusFound)
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(target.URL)
+
+	// srv stands in for a public host, only the target is checked
+	c := NewChecker()
+	c.Timeout = time.Second
+	dialer := &net.Dialer{Control: func(network, address string, conn syscall.RawConn) error {
+		if strings.HasSuffix(address, ":"+u.Port()) {
+			return dialControl(network, address, conn)
+		}
+		return nil
+	}}
+	c.Client.Transport.(*http.Transport).DialContext = dialer.DialContext
+
+	r := c.Check(context.Background(), srv.URL)
+	if r.OK() || !strings.Contains(r.Error, ErrBlockedAddress.Error()) {
+		t.Errorf("Check() = %+v, want %v", r, ErrBlockedAddress)
+	}
+	if hits.Load() != 0 {
+		t.Errorf("redirect target got %d requests", hits.Load())
+	}
+}
+
+func TestBlocked(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"127.0.0.1", true},
+		{"::1", true},
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"192.168.1.1", true},
+		{"fd00::1", true},
+		{"169.254.169.254", true},
+		{"fe80::1", true},
+		{"0.0.0.0", true},
+		{"::", true},
+		{"::ffff:127.0.0.1", true},
+		{"::ffff:169.254.169.254", true},
+		{"100.64.0.1", true},
+		{"100.127.255.254", true},
+		{"::ffff:100.64.0.1", true},
+		{"0.1.2.3", true},
+		{"192.0.0.8", true},
+		{"198.18.0.1", true},
+		{"203.0.113.5", true},
+		{"240.0.0.1", true},
+		{"255.255.255.255", true},
+		{"224.0.0.1", true},
+		{"ff02::1", true},
+		{"ff0e::1", true},
+		{"64:ff9b::7f00:1", true},
+		{"2001:db8::1", true},
+		{"100.63.255.255", false},
+		{"100.128.0.1", false},
+		{"93.184.216.34", false},
+		{"2606:2800:220:1:248:1893:25c8:1946", false},
+		{"::ffff:93.184.216.34", false},
+	}
+	for _, tt := range tests {
+		if got := blocked(netip.MustParseAddr(tt.addr)); got != tt.want {
+			t.Errorf("blocked(%s) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestCheckRedirect(t *testing.T) {
+	via := func(n int) []*http.Request { return make([]*http.Request, n) }
+	req := func(rawURL string) *http.Request {
+		r, _ := http.NewRequest(http.MethodGet, rawURL, nil)
+		return r
+	}
+	if err := checkRedirect(req("https://example.com/next"), via(1)); err != nil {
+		t.Errorf("redirect to https refused: %v", err)
+	}
+	if err := checkRedirect(req("ftp://example.com/file"), via(1)); err == nil {
+		t.Error("redirect to ftp followed")
+	}
+	if err := checkRedirect(req("https://example.com/next"), via(MaxRedirects)); err == nil {
+		t.Errorf("followed more than %d redirects", MaxRedirects)
+	}
+}
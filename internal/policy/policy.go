@@ -0,0 +1,280 @@
+package policy
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/idna"
+)
+
+const (
+	ActionAllow = "allow"
+	ActionBlock = "block"
+)
+
+// Rule matches destination hosts. A pattern is either an exact domain
+// ("example.com"), a wildcard suffix matching every subdomain
+// ("*.example.com") or a regular expression between slashes ("/paypa1/").
+type Rule struct {
+	Action  string
+	Pattern string
+	Line    int
+
+	exact  string
+	suffix string
+	re     *regexp.Regexp
+}
+
+func (r *Rule) Match(host string) bool {
+	switch {
+	case r.re != nil:
+		return r.re.MatchString(host)
+	case r.suffix != "":
+		return strings.HasSuffix(host, r.suffix)
+	default:
+		return host == r.exact
+	}
+}
+
+func (r *Rule) String() string {
+	return fmt.Sprintf("%s %s (line %d)", r.Action, r.Pattern, r.Line)
+}
+
+// Decision is the outcome of checking a host against the policy.
+type Decision struct {
+	Allowed bool
+	Rule    *Rule // the rule that decided, nil when the default applied
+}
+
+func (d Decision) Reason() string {
+	if d.Rule != nil {
+		return d.Rule.String()
+	}
+	return "default policy"
+}
+
+// Status describes the currently loaded policy.
+type Status struct {
+	Path          string
+	LoadedAt      time.Time
+	AllowRules    int
+	BlockRules    int
+	DefaultAction string
+	LastError     error
+}
+
+type ruleset struct {
+	allow         []*Rule
+	block         []*Rule
+	defaultAction string
+}
+
+// Engine holds the domain policy loaded from a file. Allow rules win over
+// block rules, and hosts matching neither get the default action, which is
+// "allow" unless the file contains a "default block" line.
+type Engine struct {
+	path string
+
+	mu       sync.RWMutex
+	rules    ruleset
+	loadedAt time.Time
+	modTime  time.Time
+	lastErr  error
+}
+
+// New returns an engine for the policy file at path. An empty path gives an
+// engine without rules that allows everything.
+func New(path string) *Engine {
+	return &Engine{
+		path:  path,
+		rules: ruleset{defaultAction: ActionAllow},
+	}
+}
+
+// Check decides whether links to host are allowed.
+func (e *Engine) Check(host string) Decision {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	for _, rule := range e.rules.allow {
+		if rule.Match(host) {
+			return Decision{Allowed: true, Rule: rule}
+		}
+	}
+	for _, rule := range e.rules.block {
+		if rule.Match(host) {
+			return Decision{Allowed: false, Rule: rule}
+		}
+	}
+	return Decision{Allowed: e.rules.defaultAction == ActionAllow}
+}
+
+// Reload re-reads the policy file. On error the previous rules stay active.
+func (e *Engine) Reload() error {
+	if e.path == "" {
+		return fmt.Errorf("no policy file configured")
+	}
+
+	info, err := os.Stat(e.path)
+	if err == nil {
+		var rules ruleset
+		rules, err = parseFile(e.path)
+		if err == nil {
+			e.mu.Lock()
+			e.rules = rules
+			e.loadedAt = time.Now()
+			e.modTime = info.ModTime()
+			e.lastErr = nil
+			e.mu.Unlock()
+			fmt.Printf("Loaded domain policy from %s: %d allow, %d block rules\n", e.path, len(rules.allow), len(rules.block))
+			return nil
+		}
+	}
+
+	e.mu.Lock()
+	e.lastErr = err
+	if info != nil {
+		// Don't retry the same broken file on every poll
+		e.modTime = info.ModTime()
+	}
+	e.mu.Unlock()
+	return err
+}
+
+// Watch reloads the policy file whenever its modification time changes.
+func (e *Engine) Watch(interval time.Duration, stop <-chan struct{}) {
+	if e.path == "" {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			info, err := os.Stat(e.path)
+			if err != nil {
+				continue
+			}
+			e.mu.RLock()
+			changed := !info.ModTime().Equal(e.modTime)
+			e.mu.RUnlock()
+			if changed {
+				if err := e.Reload(); err != nil {
+					fmt.Println("Failed to reload domain policy:", err)
+				}
+			}
+		}
+	}
+}
+
+func (e *Engine) Status() Status {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return Status{
+		Path:          e.path,
+		LoadedAt:      e.loadedAt,
+		AllowRules:    len(e.rules.allow),
+		BlockRules:    len(e.rules.block),
+		DefaultAction: e.rules.defaultAction,
+		LastError:     e.lastErr,
+	}
+}
+
+// parseFile reads one rule per line: "<allow|block> <pattern>" or
+// "default <allow|block>". Blank lines and lines starting with # are ignored.
+func parseFile(path string) (ruleset, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return ruleset{}, err
+	}
+	defer f.Close()
+
+	rules := ruleset{defaultAction: ActionAllow}
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		action, pattern, ok := strings.Cut(line, " ")
+		pattern = strings.TrimSpace(pattern)
+		if !ok || pattern == "" {
+			return ruleset{}, fmt.Errorf("%s:%d: expected \"<allow|block> <pattern>\"", path, n)
+		}
+
+		if action == "default" {
+			if pattern != ActionAllow && pattern != ActionBlock {
+				return ruleset{}, fmt.Errorf("%s:%d: default must be allow or block", path, n)
+			}
+			rules.defaultAction = pattern
+			continue
+		}
+
+		rule, err := parseRule(action, pattern)
+		if err != nil {
+			return ruleset{}, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		rule.Line = n
+
+		if action == ActionAllow {
+			rules.allow = append(rules.allow, rule)
+		} else {
+			rules.block = append(rules.block, rule)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return ruleset{}, err
+	}
+	return rules, nil
+}
+
+func parseRule(action, pattern string) (*Rule, error) {
+	if action != ActionAllow && action != ActionBlock {
+		return nil, fmt.Errorf("unknown action %q", action)
+	}
+	rule, err := ParsePattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+	rule.Action = action
+	return rule, nil
+}
+
+// ParsePattern parses a pattern in the policy file syntax into a rule
+// without an action, for matching hosts outside of the policy.
+func ParsePattern(pattern string) (*Rule, error) {
+	rule := &Rule{Pattern: pattern}
+
+	switch {
+	case len(pattern) > 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/"):
+		re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex %s: %w", pattern, err)
+		}
+		rule.re = re
+	case strings.HasPrefix(pattern, "*."):
+		domain, err := idna.Lookup.ToASCII(pattern[2:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid domain %s: %w", pattern, err)
+		}
+		rule.suffix = "." + domain
+	default:
+		domain, err := idna.Lookup.ToASCII(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid domain %s: %w", pattern, err)
+		}
+		rule.exact = domain
+	}
+	return rule, nil
+}
@@ -0,0 +1,165 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writePolicy writes a policy file into a temporary directory and returns
+// its path.
+func writePolicy(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.txt")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func loadPolicy(t *testing.T, content string) *Engine {
+	t.Helper()
+	e := New(writePolicy(t, content))
+	if err := e.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestCheck(t *testing.T) {
+	e := loadPolicy(t, `
+# Phishing domains
+block evil.example
+block *.bad.example
+block /paypa1/
+allow good.bad.example
+allow *.paypa1.example
+`)
+
+	tests := []struct {
+		name    string
+		host    string
+		allowed bool
+		rule    string // pattern of the deciding rule, empty for the default
+	}{
+		{"exact", "evil.example", false, "evil.example"},
+		{"exact is case insensitive", "EVIL.example", false, "evil.example"},
+		{"exact ignores trailing dot", "evil.example.", false, "evil.example"},
+		{"exact doesn't match subdomain", "www.evil.example", true, ""},
+		{"wildcard subdomain", "www.bad.example", false, "*.bad.example"},
+		{"wildcard nested subdomain", "a.b.bad.example", false, "*.bad.example"},
+		{"wildcard doesn't match apex", "bad.example", true, ""},
+		{"wildcard doesn't match lookalike", "notbad.example", true, ""},
+		{"regex", "secure-paypa1.com", false, "/paypa1/"},
+		{"allow wins over exact block", "good.bad.example", true, "good.bad.example"},
+		{"allow wins over regex block", "login.paypa1.example", true, "*.paypa1.example"},
+		{"no rule", "example.com", true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := e.Check(tt.host)
+			if d.Allowed != tt.allowed {
+				t.Errorf("Check(%q).Allowed = %v, want %v (%s)", tt.host, d.Allowed, tt.allowed, d.Reason())
+			}
+			var got string
+			if d.Rule != nil {
+				got = d.Rule.Pattern
+			}
+			if got != tt.rule {
+				t.Errorf("Check(%q) decided by %q, want %q", tt.host, got, tt.rule)
+			}
+		})
+	}
+}
+
+func TestCheckDefault(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		allowed bool
+	}{
+		{"no default line", "block evil.example\n", true},
+		{"default allow", "default allow\n", true},
+		{"default block", "default block\nallow example.com\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := loadPolicy(t, tt.content)
+			if d := e.Check("other.example"); d.Allowed != tt.allowed || d.Rule != nil {
+				t.Errorf("Check() = %v by %v, want %v by the default", d.Allowed, d.Rule, tt.allowed)
+			}
+		})
+	}
+
+	if d := New("").Check("example.com"); !d.Allowed {
+		t.Error("engine without a file blocked a host")
+	}
+}
+
+func TestIDNAPatterns(t *testing.T) {
+	e := loadPolicy(t, "block bücher.example\nblock *.münchen.example\n")
+	for _, host := range []string{"xn--bcher-kva.example", "www.xn--mnchen-3ya.example"} {
+		if e.Check(host).Allowed {
+			t.Errorf("Check(%q) allowed, want blocked by its unicode pattern", host)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"unknown action", "deny evil.example\n"},
+		{"missing pattern", "block\n"},
+		{"bad default", "default maybe\n"},
+		{"bad regex", "block /[/\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseFile(writePolicy(t, tt.content)); err == nil {
+				t.Errorf("parseFile(%q) succeeded", tt.content)
+			}
+		})
+	}
+}
+
+func TestReload(t *testing.T) {
+	path := writePolicy(t, "block evil.example\n")
+	e := New(path)
+	if err := e.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if e.Check("evil.example").Allowed {
+		t.Fatal("evil.example allowed after the first load")
+	}
+
+	// New rules replace the old ones
+	if err := os.WriteFile(path, []byte("block other.example\ndefault allow\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if !e.Check("evil.example").Allowed || e.Check("other.example").Allowed {
+		t.Error("reload didn't replace the rules")
+	}
+
+	// A broken file keeps the previous rules
+	if err := os.WriteFile(path, []byte("block /[/\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Reload(); err == nil {
+		t.Fatal("Reload() of a broken file succeeded")
+	}
+	if e.Check("other.example").Allowed {
+		t.Error("broken file dropped the previous rules")
+	}
+	if st := e.Status(); st.LastError == nil || st.BlockRules != 1 {
+		t.Errorf("Status() = %+v, want the error and the previous rules", st)
+	}
+
+	if err := New("").Reload(); err == nil {
+		t.Error("Reload() without a file succeeded")
+	}
+}
@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Response header metadata set on every limited call. The HTTP gateway maps
+// them to the X-RateLimit-* and Retry-After headers.
+const (
+	HeaderLimit      = "x-ratelimit-limit"
+	HeaderRemaining  = "x-ratelimit-remaining"
+	HeaderReset      = "x-ratelimit-reset"
+	HeaderRetryAfter = "retry-after"
+)
+
+// RuleFunc picks the bucket key and limit for a call. Returning ok=false
+// leaves the call unlimited. A returned error is sent back to the caller.
+type RuleFunc func(ctx context.Context, fullMethod string) (key string, limit Limit, ok bool, err error)
+
+// UnaryServerInterceptor enforces the limits chosen by rule on every unary
+// call, so direct gRPC clients are limited the same way as gateway traffic.
+func UnaryServerInterceptor(l *Limiter, rule RuleFunc) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		err := l.check(ctx, rule, info.FullMethod, func(md metadata.MD) { grpc.SetHeader(ctx, md) })
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming calls,
+// which are limited once when the stream opens.
+func StreamServerInterceptor(l *Limiter, rule RuleFunc) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := l.check(ss.Context(), rule, info.FullMethod, func(md metadata.MD) { ss.SetHeader(md) })
+		if err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// check takes a token from the bucket rule picks for the call and reports
+// the bucket's state through setHeader.
+func (l *Limiter) check(ctx context.Context, rule RuleFunc, fullMethod string, setHeader func(metadata.MD)) error {
+	key, limit, ok, err := rule(ctx, fullMethod)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+
+	res := l.Allow(ctx, key, limit)
+
+	md := metadata.Pairs(
+		HeaderLimit, strconv.Itoa(res.Limit),
+		HeaderRemaining, strconv.Itoa(res.Remaining),
+		HeaderReset, seconds(res.Reset),
+	)
+	if !res.Allowed {
+		md.Set(HeaderRetryAfter, seconds(res.RetryAfter))
+	}
+	setHeader(md)
+
+	if !res.Allowed {
+		return status.Errorf(codes.ResourceExhausted, "Too many requests. Try again in %s seconds.", seconds(res.RetryAfter))
+	}
+	return nil
+}
+
+// seconds rounds up so clients never retry before a token is available.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,181 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Limit is a token bucket holding up to Rate tokens, refilled at Rate tokens
+// per Window.
+type Limit struct {
+	Rate   int
+	Window time.Duration
+}
+
+// Result is the outcome of a single Allow call.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration // time until the next token, only set when denied
+	Reset      time.Duration // time until the bucket is full again
+}
+
+// The whole refill-and-take runs inside Redis so concurrent requests and
+// crashed processes can never leave a bucket in a half-updated state.
+var tokenBucketScript = redis.NewScript(`
+local key = KEYS[1]
+local capacity = tonumber(ARGV[1])
+local window_ms = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local rate = capacity / window_ms
+
+local data = redis.call('HMGET', key, 'tokens', 'ts')
+local tokens = tonumber(data[1])
+local ts = tonumber(data[2])
+if tokens == nil or ts == nil then
+  tokens = capacity
+  ts = now
+end
+
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+local retry_ms = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+else
+  retry_ms = math.ceil((1 - tokens) / rate)
+end
+
+redis.call('HSET', key, 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', key, window_ms)
+
+local reset_ms = math.ceil((capacity - tokens) / rate)
+return {allowed, math.floor(tokens), retry_ms, reset_ms}
+`)
+
+// Limiter enforces token buckets in Redis and falls back to in-memory buckets
+// while Redis is unavailable, so an outage never disables rate limiting.
+type Limiter struct {
+	rdb *redis.Client
+
+	mu    sync.Mutex
+	local map[string]*bucket
+
+	// fallback is set while Redis is failing, so the switch is logged once
+	fallback atomic.Bool
+
+	now func() time.Time
+}
+
+type bucket struct {
+	tokens float64
+	ts     time.Time
+}
+
+// maxLocalBuckets bounds the fallback map. Idle buckets are dropped first,
+// then the least recently used ones down to pruneTarget.
+const (
+	maxLocalBuckets = 10000
+	pruneTarget     = maxLocalBuckets * 9 / 10
+)
+
+func New(rdb *redis.Client) *Limiter {
+	return &Limiter{
+		rdb:   rdb,
+		local: make(map[string]*bucket),
+		now:   time.Now,
+	}
+}
+
+// Allow takes one token from the bucket identified by key.
+func (l *Limiter) Allow(ctx context.Context, key string, limit Limit) Result {
+	if limit.Rate <= 0 || limit.Window <= 0 {
+		return Result{Allowed: true}
+	}
+
+	res, err := tokenBucketScript.Run(ctx, l.rdb, []string{key}, limit.Rate, limit.Window.Milliseconds(), l.now().UnixMilli()).Int64Slice()
+	if err != nil {
+		if l.fallback.CompareAndSwap(false, true) {
+			fmt.Println("Rate limiter falling back to memory, Redis error:", err)
+		}
+		return l.allowLocal(key, limit)
+	}
+	if l.fallback.CompareAndSwap(true, false) {
+		fmt.Println("Rate limiter back on Redis")
+	}
+
+	return Result{
+		Allowed:    res[0] == 1,
+		Limit:      limit.Rate,
+		Remaining:  int(res[1]),
+		RetryAfter: time.Duration(res[2]) * time.Millisecond,
+		Reset:      time.Duration(res[3]) * time.Millisecond,
+	}
+}
+
+func (l *Limiter) allowLocal(key string, limit Limit) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	capacity := float64(limit.Rate)
+	rate := capacity / float64(limit.Window)
+
+	b, ok := l.local[key]
+	if !ok {
+		if len(l.local) >= maxLocalBuckets {
+			l.prune(now, limit.Window)
+		}
+		b = &bucket{tokens: capacity, ts: now}
+		l.local[key] = b
+	}
+
+	b.tokens = math.Min(capacity, b.tokens+float64(now.Sub(b.ts))*rate)
+	b.ts = now
+
+	result := Result{Limit: limit.Rate}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration(math.Ceil((1 - b.tokens) / rate))
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = time.Duration(math.Ceil((capacity - b.tokens) / rate))
+	return result
+}
+
+// prune drops buckets that have been idle long enough to be full again. If
+// that isn't enough, as when many clients are active at once, the least
+// recently used buckets go too.
+func (l *Limiter) prune(now time.Time, window time.Duration) {
+	for key, b := range l.local {
+		if now.Sub(b.ts) > window {
+			delete(l.local, key)
+		}
+	}
+	if len(l.local) < maxLocalBuckets {
+		return
+	}
+
+	keys := make([]string, 0, len(l.local))
+	for key := range l.local {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		return l.local[a].ts.Compare(l.local[b].ts)
+	})
+	for _, key := range keys[:len(keys)-pruneTarget] {
+		delete(l.local, key)
+	}
+}
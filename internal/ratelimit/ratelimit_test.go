@@ -0,0 +1,124 @@
+package ratelimit
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisClient connects to the Redis at REDIS_ADDR, or skips the test when
+// there is none. Tests use a database of their own and empty it afterwards.
+func redisClient(t *testing.T) *redis.Client {
+	t.Helper()
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		addr = "localhost:6379"
+	}
+	rdb := redis.NewClient(&redis.Options{Addr: addr, DB: 15})
+	if err := rdb.Ping(context.Background()).Err(); err != nil {
+		t.Skipf("Redis not available at %s: %v", addr, err)
+	}
+	t.Cleanup(func() {
+		rdb.FlushDB(context.Background())
+		rdb.Close()
+	})
+	return rdb
+}
+
+// unreachable makes the limiter fall back to its in-memory buckets.
+func unreachable() *redis.Client {
+	return redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 10 * time.Millisecond})
+}
+
+type step struct {
+	advance       time.Duration
+	key           string
+	wantAllowed   bool
+	wantRemaining int
+	wantRetry     time.Duration
+	wantReset     time.Duration
+}
+
+// A bucket of 3 tokens refilled at one token per second.
+var bucketSteps = []step{
+	{0, "a", true, 2, 0, time.Second},
+	{0, "a", true, 1, 0, 2 * time.Second},
+	{0, "a", true, 0, 0, 3 * time.Second},
+	{0, "a", false, 0, time.Second, 3 * time.Second},
+	{0, "b", true, 2, 0, time.Second}, // Buckets are per key
+	{500 * time.Millisecond, "a", false, 0, 500 * time.Millisecond, 2500 * time.Millisecond},
+	{500 * time.Millisecond, "a", true, 0, 0, 3 * time.Second},
+	{1500 * time.Millisecond, "a", true, 0, 0, 2500 * time.Millisecond},
+	{time.Minute, "a", true, 2, 0, time.Second}, // Never more than the capacity
+}
+
+func runSteps(t *testing.T, l *Limiter, prefix string) {
+	t.Helper()
+	now := time.UnixMilli(1_800_000_000_000)
+	l.now = func() time.Time { return now }
+	limit := Limit{Rate: 3, Window: 3 * time.Second}
+
+	for i, s := range bucketSteps {
+		now = now.Add(s.advance)
+		r := l.Allow(context.Background(), prefix+s.key, limit)
+		if r.Allowed != s.wantAllowed || r.Remaining != s.wantRemaining || r.RetryAfter != s.wantRetry || r.Reset != s.wantReset || r.Limit != limit.Rate {
+			t.Errorf("step %d: Allow() = %+v, want allowed %v remaining %d retry %s reset %s",
+				i, r, s.wantAllowed, s.wantRemaining, s.wantRetry, s.wantReset)
+		}
+	}
+}
+
+func TestAllowLocal(t *testing.T) {
+	runSteps(t, New(unreachable()), "local:")
+}
+
+func TestAllowRedis(t *testing.T) {
+	runSteps(t, New(redisClient(t)), "redis:")
+}
+
+func TestAllowWithoutLimit(t *testing.T) {
+	l := New(unreachable())
+	for _, limit := range []Limit{{}, {Rate: 0, Window: time.Minute}, {Rate: 10}} {
+		if r := l.Allow(context.Background(), "k", limit); !r.Allowed {
+			t.Errorf("Allow() with %+v = %+v, want allowed", limit, r)
+		}
+	}
+}
+
+func TestPruneDropsIdleBuckets(t *testing.T) {
+	l := New(unreachable())
+	now := time.Now()
+	l.local["idle"] = &bucket{tokens: 0, ts: now.Add(-2 * time.Minute)}
+	l.local["busy"] = &bucket{tokens: 0, ts: now.Add(-time.Second)}
+
+	l.prune(now, time.Minute)
+	if _, ok := l.local["idle"]; ok {
+		t.Error("idle bucket kept")
+	}
+	if _, ok := l.local["busy"]; !ok {
+		t.Error("busy bucket dropped")
+	}
+}
+
+func TestPruneEvictsOldestBuckets(t *testing.T) {
+	l := New(unreachable())
+	now := time.Now()
+	for i := range maxLocalBuckets {
+		l.local[strconv.Itoa(i)] = &bucket{tokens: 0, ts: now.Add(time.Duration(i-maxLocalBuckets) * time.Millisecond)}
+	}
+
+	l.prune(now, time.Minute)
+	if len(l.local) != pruneTarget {
+		t.Fatalf("%d buckets left, want %d", len(l.local), pruneTarget)
+	}
+	if _, ok := l.local["0"]; ok {
+		t.Error("oldest bucket kept")
+	}
+	if _, ok := l.local[strconv.Itoa(maxLocalBuckets-1)]; !ok {
+		t.Error("newest bucket dropped")
+	}
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+
+	"tinyurl/internal/policy"
+	pb "tinyurl/proto/tinyurl/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MaxAccessEntries is the number of entries each access list can have.
+const MaxAccessEntries = 100
+
+// AccessPolicy limits who can follow a link. Deny wins over allow, and
+// empty lists don't restrict anything.
+type AccessPolicy struct {
+	AllowIPs       []string `json:"allow_ips,omitempty"`       // CIDR prefixes
+	DenyIPs        []string `json:"deny_ips,omitempty"`        // CIDR prefixes
+	AllowReferrers []string `json:"allow_referrers,omitempty"` // Domain patterns as in the domain policy
+}
+
+// allows reports whether a visitor from ip, coming from referrer, may
+// follow the link. Visitors without a known IP or referrer don't pass the
+// lists that need one. The referrer is only checked if checkReferrer is set.
+func (a *AccessPolicy) allows(ip, referrer string, checkReferrer bool) bool {
+	if a == nil {
+		return true
+	}
+
+	addr, err := netip.ParseAddr(ip)
+	known := err == nil
+	addr = addr.Unmap()
+	if known && containsAddr(a.DenyIPs, addr) {
+		return false
+	}
+	if len(a.AllowIPs) > 0 && (!known || !containsAddr(a.AllowIPs, addr)) {
+		return false
+	}
+
+	if checkReferrer && len(a.AllowReferrers) > 0 {
+		host := hostOf(referrer)
+		if host == "" {
+			return false
+		}
+		for _, pattern := range a.AllowReferrers {
+			if rule, err := policy.ParsePattern(pattern); err == nil && rule.Match(host) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func containsAddr(prefixes []string, addr netip.Addr) bool {
+	for _, p := range prefixes {
+		if prefix, err := netip.ParsePrefix(p); err == nil && prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func accessDeniedError() error {
+	return errorWithInfo(codes.PermissionDenied, "You don't have access to this link", ReasonAccessDenied, nil)
+}
+
+// RefererMetadata is the gRPC metadata carrying the Referer of the visitor.
+// The gateway maps the HTTP Referer header to it.
+const RefererMetadata = "referer"
+
+// checkAccess enforces the access policy of a link on the caller, whose IP
+// is resolved the same way as for rate limiting. Visitors of a password
+// protected link had their referrer checked when unlocking it, after which
+// the referrer is the password form.
+func (s *TinyURLService) checkAccess(ctx context.Context, link *Link, unlocked bool) error {
+	if link.Access == nil {
+		return nil
+	}
+	var ip string
+	if s.ClientIPs != nil {
+		ip = s.ClientIPs.FromContext(ctx)
+	}
+	var referrer string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(RefererMetadata); len(v) > 0 {
+			referrer = v[0]
+		}
+	}
+	if !link.Access.allows(ip, referrer, !unlocked) {
+		return accessDeniedError()
+	}
+	return nil
+}
+
+func checkAccessPolicy(a *pb.AccessPolicy) (*AccessPolicy, error) {
+	if a == nil {
+		return nil, nil
+	}
+	allowIPs, err := checkPrefixes("allow_ips", a.AllowIps)
+	if err != nil {
+		return nil, err
+	}
+	denyIPs, err := checkPrefixes("deny_ips", a.DenyIps)
+	if err != nil {
+		return nil, err
+	}
+	if len(a.AllowReferrers) > MaxAccessEntries {
+		return nil, status.Errorf(codes.InvalidArgument, "access.allow_referrers can have at most %d entries", MaxAccessEntries)
+	}
+	var referrers []string
+	for _, p := range a.AllowReferrers {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if _, err := policy.ParsePattern(p); p == "" || err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "access.allow_referrers: invalid pattern %q", p)
+		}
+		referrers = append(referrers, p)
+	}
+
+	if len(allowIPs)+len(denyIPs)+len(referrers) == 0 {
+		return nil, nil
+	}
+	return &AccessPolicy{AllowIPs: allowIPs, DenyIPs: denyIPs, AllowReferrers: referrers}, nil
+}
+
+// checkPrefixes normalizes CIDR prefixes, turning single addresses into
+// prefixes of their own.
+func checkPrefixes(field string, values []string) ([]string, error) {
+	if len(values) > MaxAccessEntries {
+		return nil, status.Errorf(codes.InvalidArgument, "access.%s can have at most %d entries", field, MaxAccessEntries)
+	}
+	var out []string
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		prefix, err := netip.ParsePrefix(v)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(v)
+			if addrErr != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "access.%s: invalid CIDR %q", field, v)
+			}
+			prefix = netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen())
+		}
+		out = append(out, prefix.Masked().String())
+	}
+	return out, nil
+}
+
+func accessPolicyToProto(a *AccessPolicy) *pb.AccessPolicy {
+	if a == nil {
+		return nil
+	}
+	return &pb.AccessPolicy{AllowIps: a.AllowIPs, DenyIps: a.DenyIPs, AllowReferrers: a.AllowReferrers}
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"slices"
+	"testing"
+
+	pb "tinyurl/proto/tinyurl/v1"
+)
+
+func TestAccessPolicyAllows(t *testing.T) {
+	office := &AccessPolicy{
+		AllowIPs: []string{"203.0.113.0/24", "10.8.0.0/16", "2001:db8::/32"},
+		DenyIPs:  []string{"10.8.99.0/24", "2001:db8:bad::/48"},
+	}
+	partners := &AccessPolicy{AllowReferrers: []string{"partner.com", "*.shop.example", "/^news[0-9]+\\.example$/"}}
+	blocklist := &AccessPolicy{DenyIPs: []string{"198.51.100.7/32"}}
+
+	tests := []struct {
+		name          string
+		policy        *AccessPolicy
+		ip            string
+		referrer      string
+		checkReferrer bool
+		want          bool
+	}{
+		{"no policy", nil, "", "", true, true},
+		{"allowed ipv4", office, "203.0.113.10", "", true, true},
+		{"allowed second range", office, "10.8.1.2", "", true, true},
+		{"outside allow list", office, "198.51.100.1", "", true, false},
+		{"deny wins over allow", office, "10.8.99.5", "", true, false},
+		{"allowed ipv6", office, "2001:db8:1::1", "", true, true},
+		{"denied ipv6", office, "2001:db8:bad::1", "", true, false},
+		{"ipv4 mapped ipv6", office, "::ffff:203.0.113.10", "", true, true},
+		{"unknown ip with allow list", office, "", "", true, false},
+		{"garbage ip with allow list", office, "unknown", "", true, false},
+		{"deny list only", blocklist, "198.51.100.8", "", true, true},
+		{"denied by deny list", blocklist, "198.51.100.7", "", true, false},
+		{"unknown ip with deny list only", blocklist, "", "", true, true},
+
+		{"exact referrer", partners, "", "https://partner.com/page", true, true},
+		{"referrer is case insensitive", partners, "", "https://PARTNER.com/", true, true},
+		{"subdomain needs a wildcard", partners, "", "https://www.partner.com/", true, false},
+		{"wildcard referrer", partners, "", "https://eu.shop.example/cart", true, true},
+		{"wildcard needs a subdomain", partners, "", "https://shop.example/", true, false},
+		{"lookalike referrer", partners, "", "https://partner.com.evil.net/", true, false},
+		{"regex referrer", partners, "", "https://news42.example/", true, true},
+		{"regex mismatch", partners, "", "https://newsx.example/", true, false},
+		{"missing referrer", partners, "", "", true, false},
+		{"referrer not checked", partners, "", "", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.allows(tt.ip, tt.referrer, tt.checkReferrer); got != tt.want {
+				t.Errorf("allows(%q, %q, %v) = %v, want %v", tt.ip, tt.referrer, tt.checkReferrer, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckAccessPolicy(t *testing.T) {
+	got, err := checkAccessPolicy(&pb.AccessPolicy{
+		AllowIps:       []string{" 10.1.2.3/8 ", "192.0.2.1", "::ffff:198.51.100.7", "2001:db8::1/32"},
+		DenyIps:        []string{"10.9.0.0/16"},
+		AllowReferrers: []string{" Partner.COM ", "*.shop.example"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"10.0.0.0/8", "192.0.2.1/32", "198.51.100.7/32", "2001:db8::/32"}; !slices.Equal(got.AllowIPs, want) {
+		t.Errorf("AllowIPs = %q, want %q", got.AllowIPs, want)
+	}
+	if want := []string{"partner.com", "*.shop.example"}; !slices.Equal(got.AllowReferrers, want) {
+		t.Errorf("AllowReferrers = %q, want %q", got.AllowReferrers, want)
+	}
+
+	if got, err := checkAccessPolicy(&pb.AccessPolicy{}); got != nil || err != nil {
+		t.Errorf("empty policy = %+v, %v, want nil", got, err)
+	}
+
+	for _, bad := range []*pb.AccessPolicy{
+		{AllowIps: []string{"10.0.0.0/33"}},
+		{DenyIps: []string{"example.com"}},
+		{AllowReferrers: []string{"/[/"}},
+		{AllowReferrers: []string{" "}},
+		{AllowIps: make([]string, MaxAccessEntries+1)},
+	} {
+		if _, err := checkAccessPolicy(bad); err == nil {
+			t.Errorf("checkAccessPolicy(%v) accepted", bad)
+		}
+	}
+}
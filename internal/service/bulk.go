@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"tinyurl/internal/policy"
+	pb "tinyurl/proto/tinyurl/v1"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// BulkUpdateLinks walks every link with SCAN, one batch at a time, and
+// applies the action to the links with any destination host matching,
+// including fallbacks and the destinations of rules, variants and schedules.
+func (s *TinyURLService) BulkUpdateLinks(req *pb.BulkUpdateLinksRequest, stream pb.TinyURL_BulkUpdateLinksServer) error {
+	ctx := stream.Context()
+	if err := s.requireAdmin(ctx); err != nil {
+		return err
+	}
+
+	if len(req.Patterns) == 0 {
+		return status.Error(codes.InvalidArgument, "patterns is required")
+	}
+	rules := make([]*policy.Rule, len(req.Patterns))
+	for i, p := range req.Patterns {
+		rule, err := policy.ParsePattern(strings.TrimSpace(p))
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "Invalid pattern: %v", err)
+		}
+		rules[i] = rule
+	}
+
+	var newURL string
+	switch req.Action {
+	case pb.BulkAction_BULK_ACTION_DISABLE, pb.BulkAction_BULK_ACTION_DELETE:
+	case pb.BulkAction_BULK_ACTION_REPOINT:
+		var err error
+		newURL, err = s.checkDestination(req.NewLongUrl)
+		if err != nil {
+			return status.Errorf(status.Code(err), "new_long_url: %s", status.Convert(err).Message())
+		}
+		if matchAny(rules, hostOf(newURL)) {
+			return status.Error(codes.InvalidArgument, "new_long_url must not match the patterns")
+		}
+	default:
+		return status.Error(codes.InvalidArgument, "action is required")
+	}
+
+	batchSize := int64(req.BatchSize)
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	batchSize = min(batchSize, 1000)
+
+	progress := &pb.BulkUpdateProgress{}
+	err := s.scanLinks(ctx, batchSize, func(links []linkEntry, scanned int64, done bool) error {
+		var matches []linkEntry
+		for _, e := range links {
+			if matchLink(rules, e.link) {
+				matches = append(matches, e)
+			}
+		}
+		progress.Scanned += scanned
+		progress.Matched += int64(len(matches))
+
+		if !req.DryRun && len(matches) > 0 {
+			updated, err := s.applyBulk(ctx, req, rules, newURL, matches)
+			if err != nil {
+				return status.Errorf(codes.Internal, "Failed to update links: %v", err)
+			}
+			progress.Updated += updated
+		}
+
+		progress.Matches = progress.Matches[:0]
+		for _, m := range matches {
+			progress.Matches = append(progress.Matches, &pb.BulkMatch{ShortCode: m.code, LongUrl: m.link.LongURL})
+		}
+		progress.Done = done
+		return stream.Send(progress)
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return status.Errorf(codes.Internal, "Redis error: %v", err)
+	}
+
+	fmt.Printf("Bulk %s on %v: %d matched, %d updated (dry run: %v)\n", req.Action, req.Patterns, progress.Matched, progress.Updated, req.DryRun)
+	return nil
+}
+
+// applyBulk runs the action on one batch in a single pipeline and returns
+// the number of links changed.
+func (s *TinyURLService) applyBulk(ctx context.Context, req *pb.BulkUpdateLinksRequest, rules []*policy.Rule, newURL string, matches []linkEntry) (int64, error) {
+	cmds := make([]redis.Cmder, len(matches))
+	_, err := s.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, m := range matches {
+			if req.Action == pb.BulkAction_BULK_ACTION_DELETE {
+				del, err := s.buryLink(ctx, pipe, m.code, m.link)
+				if err != nil {
+					return err
+				}
+				cmds[i] = del
+				continue
+			}
+
+			if req.Action == pb.BulkAction_BULK_ACTION_DISABLE {
+				m.link.Disabled = true
+				m.link.DisabledReason = req.Reason
+				m.link.DisabledLegal = req.Legal
+			} else {
+				repoint(m.link, rules, newURL)
+				pipe.SRem(ctx, brokenLinksKey, m.code)
+			}
+			data, err := json.Marshal(m.link)
+			if err != nil {
+				return err
+			}
+			cmds[i] = pipe.SetArgs(ctx, m.code, data, redis.SetArgs{Mode: "XX", KeepTTL: true})
+		}
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		return 0, err
+	}
+
+	resolution := map[pb.BulkAction]string{
+		pb.BulkAction_BULK_ACTION_DISABLE: "disabled",
+		pb.BulkAction_BULK_ACTION_DELETE:  "deleted",
+	}[req.Action]
+
+	var updated int64
+	for i, cmd := range cmds {
+		if cmd.Err() != nil {
+			continue // Expired in the meantime
+		}
+		if del, ok := cmd.(*redis.IntCmd); ok && del.Val() == 0 {
+			continue
+		}
+		updated++
+
+		if resolution != "" {
+			if err := s.resolveLinkReports(ctx, matches[i].code, resolution, req.Reason); err != nil {
+				fmt.Printf("Failed to resolve reports for %s: %v\n", matches[i].code, err)
+			}
+		}
+	}
+	return updated, nil
+}
+
+// repoint replaces every destination of the link matching the rules.
+// The old destinations' health says nothing about the new one, and would
+// keep sending visitors to the fallbacks.
+func repoint(link *Link, rules []*policy.Rule, newURL string) {
+	for _, u := range link.destinations() {
+		if matchAny(rules, hostOf(*u)) {
+			*u = newURL
+		}
+	}
+	for i := range link.Fallbacks {
+		if link.Fallbacks[i].URL == newURL {
+			link.Fallbacks[i].Health = nil
+		}
+	}
+	link.Flagged = ""
+	link.Health = nil
+
+	// The new destination has no placeholders to fill
+	if link.Template && link.LongURL == newURL {
+		link.Template = false
+		link.ParamPatterns = nil
+	}
+}
+
+func matchLink(rules []*policy.Rule, link *Link) bool {
+	for _, u := range link.destinations() {
+		if matchAny(rules, hostOf(*u)) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAny(rules []*policy.Rule, host string) bool {
+	for _, rule := range rules {
+		if rule.Match(host) {
+			return true
+		}
+	}
+	return false
+}
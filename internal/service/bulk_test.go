@@ -0,0 +1,54 @@
+package service
+
+import (
+	"testing"
+
+	"tinyurl/internal/policy"
+)
+
+func TestRepoint(t *testing.T) {
+	rule, err := policy.ParsePattern("*.bad.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := []*policy.Rule{rule}
+	const newURL = "https://good.example/"
+
+	link := &Link{
+		LongURL:       "https://ok.example/{id}",
+		Template:      true,
+		ParamPatterns: map[string]string{"id": "[0-9]+"},
+		Fallbacks:     []Fallback{{URL: "https://cdn.bad.example/", Health: &Health{Broken: true}}},
+		Rules:         []TargetRule{{URL: "https://m.bad.example/{id}"}},
+		Variants:      []Variant{{URL: "https://ok.example/b/{id}"}},
+		Schedules:     []Schedule{{URL: "https://x.bad.example/"}},
+	}
+	if !matchLink(rules, link) {
+		t.Fatal("link with matching alternate destinations not matched")
+	}
+
+	repoint(link, rules, newURL)
+	if link.LongURL != "https://ok.example/{id}" || !link.Template || link.ParamPatterns == nil {
+		t.Errorf("destination that didn't match changed: %q template=%v", link.LongURL, link.Template)
+	}
+	if link.Variants[0].URL != "https://ok.example/b/{id}" {
+		t.Errorf("variant that didn't match changed: %q", link.Variants[0].URL)
+	}
+	for name, u := range map[string]string{"fallback": link.Fallbacks[0].URL, "rule": link.Rules[0].URL, "schedule": link.Schedules[0].URL} {
+		if u != newURL {
+			t.Errorf("%s = %q, want %q", name, u, newURL)
+		}
+	}
+	if link.Fallbacks[0].Health != nil {
+		t.Error("repointed fallback kept its health")
+	}
+	if matchLink(rules, link) {
+		t.Error("link still matches after repoint")
+	}
+
+	link = &Link{LongURL: "https://www.bad.example/{id}", Template: true, ParamPatterns: map[string]string{"id": "[0-9]+"}}
+	repoint(link, rules, newURL)
+	if link.LongURL != newURL || link.Template || link.ParamPatterns != nil {
+		t.Errorf("repointed template = %q template=%v param_patterns=%v, want a plain link", link.LongURL, link.Template, link.ParamPatterns)
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// clicksLeftPrefix keys count down the visits left on links with a click
+// limit, next to the link so a visit doesn't rewrite it.
+const clicksLeftPrefix = "clicks_left:"
+
+// useClickScript takes one click off a limited link. The last click
+// replaces the link with a tombstone in the same step, so concurrent
+// visitors can never get more clicks than the limit. It returns the clicks
+// left, or -1 if there were none.
+//
+// KEYS: clicks left, link, tombstone
+// ARGV: tombstone, tombstone TTL in milliseconds (0 for none)
+var useClickScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return -1
+end
+local left = redis.call('DECR', KEYS[1])
+if left > 0 then
+	return left
+end
+redis.call('DEL', KEYS[1], KEYS[2])
+if tonumber(ARGV[2]) > 0 then
+	redis.call('SET', KEYS[3], ARGV[1], 'PX', ARGV[2])
+else
+	redis.call('DEL', KEYS[3])
+end
+return 0
+`)
+
+func checkMaxClicks(maxClicks int32) (int, error) {
+	if maxClicks < 0 {
+		return 0, status.Error(codes.InvalidArgument, "max_clicks must not be negative")
+	}
+	return int(maxClicks), nil
+}
+
+// useClick consumes a click of a limited link and reports whether there
+// was one left.
+func (s *TinyURLService) useClick(ctx context.Context, code string, link *Link) (bool, error) {
+	stone, err := json.Marshal(tombstone{
+		Code:      code,
+		ExpiredAt: time.Now(),
+		Owner:     link.Owner,
+		Reason:    ExpiredReasonMaxClicks,
+	})
+	if err != nil {
+		return false, err
+	}
+	keys := []string{clicksLeftPrefix + code, code, tombstonePrefix + code}
+	left, err := useClickScript.Run(ctx, s.rdb, keys, stone, s.TombstoneTTL.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return left >= 0, nil
+}
+
+// clicksLeft returns the clicks left on a limited link, 0 for other links.
+func (s *TinyURLService) clicksLeft(ctx context.Context, code string, link *Link) (int64, error) {
+	if link.MaxClicks == 0 {
+		return 0, nil
+	}
+	left, err := s.rdb.Get(ctx, clicksLeftPrefix+code).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return left, err
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Errors the redirect handler has to tell apart from others with the same
+// code carry a google.rpc.ErrorInfo with one of these reasons.
+const (
+	ErrorDomain         = "tinyurl"
+	ReasonLinkDisabled  = "LINK_DISABLED"
+	ReasonLinkExpired   = "LINK_EXPIRED"
+	ReasonLinkNotActive = "LINK_NOT_ACTIVE"
+
+	ReasonPasswordRequired = "PASSWORD_REQUIRED"
+	ReasonAccessDenied     = "ACCESS_DENIED"
+)
+
+// ExpiredReasonMaxClicks is the "reason" of LINK_EXPIRED errors for links
+// that reached their click limit rather than their expiry.
+const ExpiredReasonMaxClicks = "max_clicks"
+
+// ExpiredReasonDeleted is the "reason" of LINK_EXPIRED errors for links a
+// moderator deleted.
+const ExpiredReasonDeleted = "deleted"
+
+func errorWithInfo(c codes.Code, msg, reason string, metadata map[string]string) error {
+	st, err := status.New(c, msg).WithDetails(&errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   ErrorDomain,
+		Metadata: metadata,
+	})
+	if err != nil {
+		return status.Error(c, msg)
+	}
+	return st.Err()
+}
+
+// ErrorInfo returns the ErrorInfo attached to err, or nil.
+func ErrorInfo(err error) *errdetails.ErrorInfo {
+	for _, d := range status.Convert(err).Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok && info.Domain == ErrorDomain {
+			return info
+		}
+	}
+	return nil
+}
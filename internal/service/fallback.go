@@ -0,0 +1,50 @@
+package service
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MaxFallbacks is the number of fallback URLs a link can have.
+const MaxFallbacks = 5
+
+// checkFallbacks validates the fallback URLs of a link the same way as its
+// destination. Health results of fallbacks the link already had are kept.
+func (s *TinyURLService) checkFallbacks(longURL string, urls []string, current []Fallback) ([]Fallback, error) {
+	if len(urls) > MaxFallbacks {
+		return nil, status.Errorf(codes.InvalidArgument, "At most %d fallback URLs are allowed", MaxFallbacks)
+	}
+
+	seen := map[string]bool{longURL: true}
+	var fallbacks []Fallback
+	for _, raw := range urls {
+		u, err := s.checkDestination(raw)
+		if err != nil {
+			return nil, status.Errorf(status.Code(err), "fallback_urls: %s", status.Convert(err).Message())
+		}
+		if seen[u] {
+			return nil, status.Errorf(codes.InvalidArgument, "fallback_urls: %s is listed twice", u)
+		}
+		seen[u] = true
+
+		f := Fallback{URL: u}
+		for _, c := range current {
+			if c.URL == u {
+				f.Health = c.Health
+			}
+		}
+		fallbacks = append(fallbacks, f)
+	}
+	return fallbacks, nil
+}
+
+func (s *TinyURLService) checkLastResort(raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+	u, err := s.checkDestination(raw)
+	if err != nil {
+		return "", status.Errorf(status.Code(err), "last_resort_url: %s", status.Convert(err).Message())
+	}
+	return u, nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"tinyurl/internal/health"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// brokenLinksKey is the set of short codes currently considered broken.
+const brokenLinksKey = "links:broken"
+
+type Health struct {
+	Status    int       `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+	Failures  int       `json:"failures,omitempty"` // Consecutive failed checks
+	Broken    bool      `json:"broken,omitempty"`
+}
+
+// CheckLinkHealth checks the destination of every enabled link and records
+// the result on it. A link is marked broken after brokenAfter failed checks
+// in a row and recovers with the first successful one.
+func (s *TinyURLService) CheckLinkHealth(ctx context.Context, checker *health.Checker, brokenAfter int) error {
+	start := time.Now()
+	codesByURL := map[string][]string{}
+	err := s.scanLinks(ctx, 500, func(links []linkEntry, scanned int64, done bool) error {
+		for _, e := range links {
+			if e.link.Disabled {
+				continue
+			}
+			urls := []string{e.link.LongURL}
+			for _, f := range e.link.Fallbacks {
+				urls = append(urls, f.URL)
+			}
+			for _, u := range urls {
+				// Templates only become real URLs once filled in
+				if e.link.Template && len(templateParams(u)) > 0 {
+					continue
+				}
+				codesByURL[u] = append(codesByURL[u], e.code)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	urls := make([]string, 0, len(codesByURL))
+	for u := range codesByURL {
+		urls = append(urls, u)
+	}
+
+	var mu sync.Mutex
+	var checked, failed, broken int
+	checker.CheckAll(ctx, urls, func(rawURL string, r health.Result) {
+		for _, code := range codesByURL[rawURL] {
+			h, err := s.recordHealth(ctx, code, rawURL, r, brokenAfter)
+			if err != nil {
+				fmt.Printf("Failed to record health of %s: %v\n", code, err)
+				continue
+			}
+			if h == nil {
+				continue
+			}
+			mu.Lock()
+			checked++
+			if h.Failures > 0 {
+				failed++
+			}
+			if h.Broken {
+				broken++
+			}
+			mu.Unlock()
+		}
+	})
+
+	fmt.Printf("Health check of %d links done in %s: %d failing, %d broken\n", checked, time.Since(start), failed, broken)
+	return nil
+}
+
+// recordHealth stores a check result on every destination of a link that
+// uses checkedURL. The link is reloaded first so changes made while the
+// check ran aren't lost. It returns the health of the main destination, or
+// nil if the link no longer uses checkedURL there.
+func (s *TinyURLService) recordHealth(ctx context.Context, code, checkedURL string, r health.Result, brokenAfter int) (*Health, error) {
+	link, err := s.getLink(ctx, code)
+	if err == redis.Nil {
+		s.rdb.SRem(ctx, brokenLinksKey, code)
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	changed := false
+	for i, f := range link.Fallbacks {
+		if f.URL == checkedURL {
+			link.Fallbacks[i].Health = nextHealth(f.Health, r, brokenAfter)
+			changed = true
+		}
+	}
+	var h *Health
+	if link.LongURL == checkedURL {
+		h = nextHealth(link.Health, r, brokenAfter)
+		link.Health = h
+		changed = true
+	}
+	if !changed {
+		return nil, nil
+	}
+	if err := s.updateLink(ctx, code, link); err != nil {
+		return nil, err
+	}
+	if h == nil {
+		return nil, nil
+	}
+
+	if h.Broken {
+		err = s.rdb.SAdd(ctx, brokenLinksKey, code).Err()
+	} else {
+		err = s.rdb.SRem(ctx, brokenLinksKey, code).Err()
+	}
+	return h, err
+}
+
+func nextHealth(prev *Health, r health.Result, brokenAfter int) *Health {
+	h := &Health{Status: r.Status, Error: r.Error, CheckedAt: r.CheckedAt}
+	if !r.OK() {
+		h.Failures = 1
+		if prev != nil {
+			h.Failures = prev.Failures + 1
+		}
+		h.Broken = h.Failures >= brokenAfter
+	}
+	return h
+}
+
+// healthy reports whether the latest check passed. Destinations that were
+// never checked count as healthy.
+func (h *Health) healthy() bool {
+	return h == nil || h.Failures == 0
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"tinyurl/internal/health"
+)
+
+func TestNextHealth(t *testing.T) {
+	ok := health.Result{Status: http.StatusOK, CheckedAt: time.Now()}
+	notFound := health.Result{Status: http.StatusNotFound, CheckedAt: time.Now()}
+	down := health.Result{Error: "connection refused", CheckedAt: time.Now()}
+
+	tests := []struct {
+		name         string
+		prev         *Health
+		result       health.Result
+		wantFailures int
+		wantBroken   bool
+	}{
+		{"first check ok", nil, ok, 0, false},
+		{"first failure", nil, down, 1, false},
+		{"second failure", &Health{Failures: 1}, notFound, 2, false},
+		{"broken after three", &Health{Failures: 2}, down, 3, true},
+		{"stays broken", &Health{Failures: 3, Broken: true}, down, 4, true},
+		{"recovers", &Health{Failures: 5, Broken: true}, ok, 0, false},
+		{"auth wall counts as ok", &Health{Failures: 1}, health.Result{Status: http.StatusForbidden}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := nextHealth(tt.prev, tt.result, 3)
+			if h.Failures != tt.wantFailures || h.Broken != tt.wantBroken {
+				t.Errorf("nextHealth() = failures %d broken %v, want %d %v", h.Failures, h.Broken, tt.wantFailures, tt.wantBroken)
+			}
+			if h.healthy() != (tt.wantFailures == 0) {
+				t.Errorf("healthy() = %v with %d failures", h.healthy(), h.Failures)
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Link is stored as JSON under its short code. Links created before the
+// model existed are plain destination URL strings and are still readable.
+type Link struct {
+	LongURL   string    `json:"long_url"`
+	CreatedAt time.Time `json:"created_at"`
+	Owner     string    `json:"owner,omitempty"`
+	Plan      string    `json:"plan,omitempty"`
+
+	// RedirectType is an HTTP status ("301", "302", ...), "meta_refresh" or
+	// "javascript". Empty means 303.
+	RedirectType string `json:"redirect_type,omitempty"`
+
+	// PathPassthrough appends the path after the short code to the
+	// destination, QueryPassthrough merges the query string into it
+	PathPassthrough  bool   `json:"path_passthrough,omitempty"`
+	QueryPassthrough string `json:"query_passthrough,omitempty"`
+
+	// Template links fill the placeholders of their destinations from the
+	// path, and ParamPatterns validate the values
+	Template      bool              `json:"template,omitempty"`
+	ParamPatterns map[string]string `json:"param_patterns,omitempty"`
+
+	// Rules send visitors to other destinations by their device, checked
+	// in order before the destination itself
+	Rules []TargetRule `json:"rules,omitempty"`
+
+	// Variants split the visitors no rule matched between destinations
+	Variants []Variant `json:"variants,omitempty"`
+
+	// NotBefore is when the link starts redirecting. Schedules switch the
+	// destination at times read in TimeZone, or the server's time zone.
+	NotBefore time.Time  `json:"not_before,omitzero"`
+	TimeZone  string     `json:"time_zone,omitempty"`
+	Schedules []Schedule `json:"schedules,omitempty"`
+
+	// PasswordHash is the bcrypt hash of the password visitors have to
+	// enter, empty for links without one
+	PasswordHash string `json:"password_hash,omitempty"`
+
+	// MaxClicks turns the link into a tombstone after this many visits,
+	// counted down in a key of its own
+	MaxClicks int `json:"max_clicks,omitempty"`
+
+	// Access limits who can follow the link
+	Access *AccessPolicy `json:"access,omitempty"`
+
+	// Interstitial shows visitors the preview page instead of redirecting
+	Interstitial bool `json:"interstitial,omitempty"`
+
+	// Flagged is set once the destination matched the threat list
+	Flagged string `json:"flagged,omitempty"`
+
+	// Disabled links are kept but no longer redirect. DisabledLegal serves
+	// them as 451 instead of 410.
+	Disabled       bool   `json:"disabled,omitempty"`
+	DisabledReason string `json:"disabled_reason,omitempty"`
+	DisabledLegal  bool   `json:"disabled_legal,omitempty"`
+
+	// Health is the result of the latest destination check
+	Health *Health `json:"health,omitempty"`
+
+	// Fallbacks are tried in order while the destination is down, and
+	// LastResort once every one of them is
+	Fallbacks  []Fallback `json:"fallbacks,omitempty"`
+	LastResort string     `json:"last_resort,omitempty"`
+}
+
+type Fallback struct {
+	URL    string  `json:"url"`
+	Health *Health `json:"health,omitempty"`
+}
+
+// Destination picks where visitors go based on the latest health checks:
+// the destination itself, the first healthy fallback, or the last resort.
+// The second result reports whether it isn't the destination itself.
+func (l *Link) Destination() (string, bool) {
+	if l.Health.healthy() {
+		return l.LongURL, false
+	}
+	for _, f := range l.Fallbacks {
+		if f.Health.healthy() {
+			return f.URL, true
+		}
+	}
+	if l.LastResort != "" {
+		return l.LastResort, true
+	}
+	return l.LongURL, false
+}
+
+// destinations points at every URL the link can send visitors to, so they
+// can be checked or replaced in place.
+func (l *Link) destinations() []*string {
+	urls := []*string{&l.LongURL}
+	for i := range l.Fallbacks {
+		urls = append(urls, &l.Fallbacks[i].URL)
+	}
+	if l.LastResort != "" {
+		urls = append(urls, &l.LastResort)
+	}
+	for i := range l.Rules {
+		urls = append(urls, &l.Rules[i].URL)
+	}
+	for i := range l.Variants {
+		urls = append(urls, &l.Variants[i].URL)
+	}
+	for i := range l.Schedules {
+		urls = append(urls, &l.Schedules[i].URL)
+	}
+	return urls
+}
+
+func decodeLink(value string) (*Link, error) {
+	if !strings.HasPrefix(value, "{") {
+		return &Link{LongURL: value}, nil
+	}
+
+	var link Link
+	if err := json.Unmarshal([]byte(value), &link); err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// linkEntry is a link together with its short code.
+type linkEntry struct {
+	code string
+	link *Link
+}
+
+// scanLinks walks every link with SCAN, calling fn with each page of up to
+// about count links. Keys containing ':' hold other data, never links.
+func (s *TinyURLService) scanLinks(ctx context.Context, count int64, fn func(links []linkEntry, scanned int64, done bool) error) error {
+	seen := map[string]bool{} // SCAN may return a key more than once
+	var cursor uint64
+	for {
+		keys, next, err := s.rdb.ScanType(ctx, cursor, "*", count, "string").Result()
+		if err != nil {
+			return err
+		}
+		cursor = next
+
+		var codes []string
+		for _, k := range keys {
+			if !strings.Contains(k, ":") && !seen[k] {
+				seen[k] = true
+				codes = append(codes, k)
+			}
+		}
+
+		var links []linkEntry
+		if len(codes) > 0 {
+			values, err := s.rdb.MGet(ctx, codes...).Result()
+			if err != nil {
+				return err
+			}
+			for i, v := range values {
+				value, ok := v.(string)
+				if !ok {
+					continue // Expired
+				}
+				if link, err := decodeLink(value); err == nil {
+					links = append(links, linkEntry{code: codes[i], link: link})
+				}
+			}
+		}
+
+		if err := fn(links, int64(len(codes)), cursor == 0); err != nil {
+			return err
+		}
+		if cursor == 0 {
+			return nil
+		}
+	}
+}
+
+// getLink loads a link, returning redis.Nil when it doesn't exist.
+func (s *TinyURLService) getLink(ctx context.Context, code string) (*Link, error) {
+	value, err := s.rdb.Get(ctx, code).Result()
+	if err != nil {
+		return nil, err
+	}
+	return decodeLink(value)
+}
+
+// errCodeTaken is returned by createLink when the code is already in use.
+var errCodeTaken = errors.New("short code taken")
+
+// createLinkScript stores a link only if its code is still free, together
+// with its tombstone and click counter, so two requests for the same alias
+// can't both succeed. It returns 0 if the code was taken.
+//
+// KEYS: link, tombstone, clicks left
+// ARGV: link, TTL in milliseconds (0 for none), tombstone (empty for none),
+// tombstone TTL in milliseconds, click limit (0 for none)
+var createLinkScript = redis.NewScript(`
+local ttl = tonumber(ARGV[2])
+local created
+if ttl > 0 then
+	created = redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ttl)
+else
+	created = redis.call('SET', KEYS[1], ARGV[1], 'NX')
+end
+if not created then
+	return 0
+end
+if ARGV[3] ~= '' then
+	redis.call('SET', KEYS[2], ARGV[3], 'PX', ARGV[4])
+end
+if tonumber(ARGV[5]) > 0 then
+	if ttl > 0 then
+		redis.call('SET', KEYS[3], ARGV[5], 'PX', ttl)
+	else
+		redis.call('SET', KEYS[3], ARGV[5])
+	end
+end
+return 1
+`)
+
+// createLink stores a new link, exp of 0 means it never expires. Links that
+// expire get a tombstone which outlives them by TombstoneTTL. It returns
+// errCodeTaken if the code is in use.
+func (s *TinyURLService) createLink(ctx context.Context, code string, link *Link, exp time.Duration) error {
+	data, err := json.Marshal(link)
+	if err != nil {
+		return err
+	}
+
+	var stone []byte
+	if exp > 0 && s.TombstoneTTL > 0 {
+		stone, err = json.Marshal(tombstone{
+			Code:      code,
+			ExpiredAt: link.CreatedAt.Add(exp),
+			Owner:     link.Owner,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	keys := []string{code, tombstonePrefix + code, clicksLeftPrefix + code}
+	created, err := createLinkScript.Run(ctx, s.rdb, keys, data, exp.Milliseconds(), stone, (exp + s.TombstoneTTL).Milliseconds(), link.MaxClicks).Int()
+	if err != nil {
+		return err
+	}
+	if created == 0 {
+		return errCodeTaken
+	}
+	return nil
+}
+
+// updateLink overwrites an existing link and keeps its expiry. It does
+// nothing if the link expired in the meantime.
+func (s *TinyURLService) updateLink(ctx context.Context, code string, link *Link) error {
+	data, err := json.Marshal(link)
+	if err != nil {
+		return err
+	}
+	err = s.rdb.SetArgs(ctx, code, data, redis.SetArgs{Mode: "XX", KeepTTL: true}).Err()
+	if err == redis.Nil {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	pb "tinyurl/proto/tinyurl/v1"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *TinyURLService) GetLink(ctx context.Context, req *pb.GetLinkRequest) (*pb.LinkInfo, error) {
+	if req.ShortCode == "" {
+		return nil, status.Error(codes.InvalidArgument, "short_code is required")
+	}
+	caller, err := s.manager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := s.getLink(ctx, req.ShortCode)
+	if err == redis.Nil {
+		return nil, status.Error(codes.NotFound, "URL not found")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "Redis error: %v", err)
+	}
+	// Don't reveal other people's links
+	if !caller.canManage(link) {
+		return nil, status.Error(codes.NotFound, "URL not found")
+	}
+
+	return s.describeLink(ctx, req.ShortCode, link)
+}
+
+func (s *TinyURLService) UpdateLink(ctx context.Context, req *pb.UpdateLinkRequest) (*pb.LinkInfo, error) {
+	if req.ShortCode == "" {
+		return nil, status.Error(codes.InvalidArgument, "short_code is required")
+	}
+	if len(req.UpdateMask.GetPaths()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "update_mask is required")
+	}
+	caller, err := s.manager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := s.getLink(ctx, req.ShortCode)
+	if err == redis.Nil {
+		return nil, status.Error(codes.NotFound, "URL not found")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "Redis error: %v", err)
+	}
+	if !caller.canManage(link) {
+		return nil, status.Error(codes.NotFound, "URL not found")
+	}
+
+	settings := req.Settings
+	if settings == nil {
+		settings = &pb.LinkSettings{}
+	}
+	for _, path := range req.UpdateMask.GetPaths() {
+		switch path {
+		case "fallback_urls":
+			link.Fallbacks, err = s.checkFallbacks(link.LongURL, settings.FallbackUrls, link.Fallbacks)
+		case "last_resort_url":
+			link.LastResort, err = s.checkLastResort(settings.LastResortUrl)
+		case "redirect_type":
+			link.RedirectType, err = redirectTypeFromProto(settings.RedirectType)
+		case "path_passthrough":
+			link.PathPassthrough = settings.PathPassthrough
+		case "query_passthrough":
+			link.QueryPassthrough, err = queryPassthroughFromProto(settings.QueryPassthrough)
+		case "param_patterns":
+			if err = checkTemplate(link.LongURL, link.Template, settings.ParamPatterns); err == nil {
+				link.ParamPatterns = settings.ParamPatterns
+			}
+		case "rules":
+			link.Rules, err = s.checkRules(link.params(), settings.Rules)
+		case "variants":
+			link.Variants, err = s.checkVariants(link.params(), settings.Variants)
+		case "not_before":
+			link.NotBefore, err = checkNotBefore(settings.NotBefore)
+		case "time_zone":
+			link.TimeZone, err = checkTimeZone(settings.TimeZone)
+		case "schedules":
+			link.Schedules, err = s.checkSchedules(link.params(), settings.Schedules)
+		case "interstitial":
+			link.Interstitial = settings.Interstitial
+		case "access":
+			link.Access, err = checkAccessPolicy(settings.Access)
+		case "password":
+			link.PasswordHash = ""
+			if settings.Password != "" {
+				link.PasswordHash, err = hashPassword(settings.Password)
+			}
+		default:
+			err = status.Errorf(codes.InvalidArgument, "Unknown field in update_mask: %s", path)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.updateLink(ctx, req.ShortCode, link); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to update link: %v", err)
+	}
+	return s.describeLink(ctx, req.ShortCode, link)
+}
+
+func (s *TinyURLService) ListBrokenLinks(ctx context.Context, req *pb.ListBrokenLinksRequest) (*pb.ListBrokenLinksResponse, error) {
+	caller, err := s.manager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.rdb.SMembers(ctx, brokenLinksKey).Result()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Redis error: %v", err)
+	}
+
+	resp := &pb.ListBrokenLinksResponse{}
+	for _, code := range members {
+		link, err := s.getLink(ctx, code)
+		if err == redis.Nil {
+			s.rdb.SRem(ctx, brokenLinksKey, code)
+			continue
+		} else if err != nil {
+			return nil, status.Errorf(codes.Internal, "Redis error: %v", err)
+		}
+		if !caller.canManage(link) || link.Health == nil || !link.Health.Broken {
+			continue
+		}
+		info, err := s.describeLink(ctx, code, link)
+		if err != nil {
+			return nil, err
+		}
+		resp.Links = append(resp.Links, info)
+	}
+	return resp, nil
+}
+
+// manager returns the caller for RPCs that manage existing links, which
+// need an API key.
+func (s *TinyURLService) manager(ctx context.Context) (Caller, error) {
+	caller, err := s.plans.CallerFromContext(ctx)
+	if err != nil {
+		return Caller{}, err
+	}
+	if !caller.Authenticated() {
+		return Caller{}, status.Error(codes.Unauthenticated, "API key required")
+	}
+	return caller, nil
+}
+
+// describeLink is linkInfo with the state kept next to the link in Redis.
+func (s *TinyURLService) describeLink(ctx context.Context, code string, link *Link) (*pb.LinkInfo, error) {
+	ttl, err := s.rdb.TTL(ctx, code).Result()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Redis error: %v", err)
+	}
+	clicksLeft, err := s.clicksLeft(ctx, code, link)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Redis error: %v", err)
+	}
+	info := linkInfo(code, link, ttl)
+	info.ClicksLeft = clicksLeft
+	return info, nil
+}
+
+func linkInfo(code string, link *Link, ttl time.Duration) *pb.LinkInfo {
+	info := &pb.LinkInfo{
+		ShortCode:      code,
+		LongUrl:        link.LongURL,
+		Owner:          link.Owner,
+		Plan:           link.Plan,
+		Flagged:        link.Flagged != "",
+		Disabled:       link.Disabled,
+		DisabledReason: link.DisabledReason,
+	}
+	if !link.CreatedAt.IsZero() {
+		info.CreatedAt = link.CreatedAt.Unix()
+	}
+	if ttl > 0 {
+		info.ExpiresAt = time.Now().Add(ttl).Unix()
+	}
+	info.Health = healthToProto(link.Health)
+	for _, f := range link.Fallbacks {
+		info.Fallbacks = append(info.Fallbacks, &pb.Fallback{Url: f.URL, Health: healthToProto(f.Health)})
+	}
+	info.LastResortUrl = link.LastResort
+	info.RedirectType = redirectTypeToProto(link.RedirectType)
+	info.PathPassthrough = link.PathPassthrough
+	info.QueryPassthrough = queryPassthroughToProto(link.QueryPassthrough)
+	info.Template = link.Template
+	info.ParamPatterns = link.ParamPatterns
+	info.Rules = rulesToProto(link.Rules)
+	info.Variants = variantsToProto(link.Variants)
+	if !link.NotBefore.IsZero() {
+		info.NotBefore = link.NotBefore.Unix()
+	}
+	info.TimeZone = link.TimeZone
+	info.Schedules = schedulesToProto(link.Schedules)
+	info.PasswordProtected = link.PasswordHash != ""
+	info.MaxClicks = int32(link.MaxClicks)
+	info.Access = accessPolicyToProto(link.Access)
+	info.Interstitial = link.Interstitial
+	return info
+}
+
+func healthToProto(h *Health) *pb.LinkHealth {
+	if h == nil {
+		return nil
+	}
+	return &pb.LinkHealth{
+		Status:              int32(h.Status),
+		Error:               h.Error,
+		CheckedAt:           h.CheckedAt.Unix(),
+		ConsecutiveFailures: int32(h.Failures),
+		Broken:              h.Broken,
+	}
+}
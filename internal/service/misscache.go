@@ -0,0 +1,66 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// missCache remembers short codes that were recently not found, so scanners
+// hammering unknown codes are answered from memory instead of Redis. Entries
+// are short lived because another instance may create the code meanwhile.
+type missCache struct {
+	ttl     time.Duration
+	maxSize int
+
+	mu      sync.Mutex
+	entries map[string]time.Time // code -> expiry
+}
+
+func newMissCache(ttl time.Duration, maxSize int) *missCache {
+	return &missCache{
+		ttl:     ttl,
+		maxSize: maxSize,
+		entries: make(map[string]time.Time),
+	}
+}
+
+func (c *missCache) Has(code string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	exp, ok := c.entries[code]
+	if !ok {
+		return false
+	}
+	if time.Now().After(exp) {
+		delete(c.entries, code)
+		return false
+	}
+	return true
+}
+
+func (c *missCache) Add(code string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if len(c.entries) >= c.maxSize {
+		for k, exp := range c.entries {
+			if now.After(exp) {
+				delete(c.entries, k)
+			}
+		}
+		// Still full, scanners are faster than the TTL. Start over rather
+		// than growing without bound.
+		if len(c.entries) >= c.maxSize {
+			clear(c.entries)
+		}
+	}
+	c.entries[code] = now.Add(c.ttl)
+}
+
+func (c *missCache) Remove(code string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, code)
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"net/url"
+	"path"
+	"strings"
+
+	pb "tinyurl/proto/tinyurl/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Query passthrough modes as stored on a link. Empty means the query of the
+// short URL is dropped.
+const (
+	queryRequestWins = "request_wins"
+	queryLinkWins    = "link_wins"
+)
+
+var queryPassthroughModes = map[pb.QueryPassthrough]string{
+	pb.QueryPassthrough_QUERY_PASSTHROUGH_DISABLED:     "",
+	pb.QueryPassthrough_QUERY_PASSTHROUGH_REQUEST_WINS: queryRequestWins,
+	pb.QueryPassthrough_QUERY_PASSTHROUGH_LINK_WINS:    queryLinkWins,
+}
+
+func queryPassthroughFromProto(q pb.QueryPassthrough) (string, error) {
+	v, ok := queryPassthroughModes[q]
+	if !ok {
+		return "", status.Error(codes.InvalidArgument, "Unknown query_passthrough")
+	}
+	return v, nil
+}
+
+func queryPassthroughToProto(v string) pb.QueryPassthrough {
+	for q, s := range queryPassthroughModes {
+		if s == v {
+			return q
+		}
+	}
+	return pb.QueryPassthrough_QUERY_PASSTHROUGH_DISABLED
+}
+
+// passthrough builds the final destination from dest and the path and query
+// the visitor added to the short URL, as far as the link allows it.
+func (l *Link) passthrough(dest, extraPath, rawQuery string) (string, error) {
+	if extraPath == "/" {
+		extraPath = ""
+	}
+	if (extraPath == "" || !l.PathPassthrough) && (rawQuery == "" || l.QueryPassthrough == "") {
+		return dest, nil
+	}
+
+	u, err := url.Parse(dest)
+	if err != nil {
+		return "", err
+	}
+
+	if extraPath != "" {
+		// Clean keeps the visitor from climbing out of the destination path
+		cleaned := path.Clean("/" + extraPath)
+		if strings.HasSuffix(extraPath, "/") && cleaned != "/" {
+			cleaned += "/"
+		}
+		u.Path = strings.TrimSuffix(u.Path, "/") + cleaned
+		u.RawPath = ""
+	}
+
+	if rawQuery != "" && l.QueryPassthrough != "" {
+		if _, err := url.ParseQuery(rawQuery); err != nil {
+			return "", status.Error(codes.InvalidArgument, "Invalid query string")
+		}
+		u.RawQuery = mergeQuery(u.RawQuery, rawQuery, l.QueryPassthrough == queryLinkWins)
+	}
+
+	return u.String(), nil
+}
+
+// mergeQuery appends the incoming query to the destination's own, keeping
+// the order and encoding of both, since some destinations care about them.
+// Keys in both come from the destination if linkWins is set, otherwise
+// from the incoming query.
+func mergeQuery(own, incoming string, linkWins bool) string {
+	ownPairs, incomingPairs := splitQuery(own), splitQuery(incoming)
+	ownKeys, incomingKeys := queryKeys(ownPairs), queryKeys(incomingPairs)
+
+	var pairs []string
+	for _, pair := range ownPairs {
+		if linkWins || !incomingKeys[queryKey(pair)] {
+			pairs = append(pairs, pair)
+		}
+	}
+	for _, pair := range incomingPairs {
+		if !linkWins || !ownKeys[queryKey(pair)] {
+			pairs = append(pairs, escapePair(pair))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+func splitQuery(rawQuery string) []string {
+	var pairs []string
+	for pair := range strings.SplitSeq(rawQuery, "&") {
+		if pair != "" {
+			pairs = append(pairs, pair)
+		}
+	}
+	return pairs
+}
+
+func queryKeys(pairs []string) map[string]bool {
+	keys := make(map[string]bool, len(pairs))
+	for _, pair := range pairs {
+		keys[queryKey(pair)] = true
+	}
+	return keys
+}
+
+// queryKey returns the unescaped key of a key=value pair.
+func queryKey(pair string) string {
+	key, _, _ := strings.Cut(pair, "=")
+	if k, err := url.QueryUnescape(key); err == nil {
+		return k
+	}
+	return key
+}
+
+// escapePair keeps a pair as it was sent, unless it has characters that
+// would end the query or break the URL, which only direct gRPC callers can
+// send.
+func escapePair(pair string) string {
+	if !strings.ContainsFunc(pair, func(r rune) bool { return r == '#' || r <= ' ' || r >= 0x7f }) {
+		return pair
+	}
+	key, value, hasValue := strings.Cut(pair, "=")
+	key, _ = url.QueryUnescape(key)
+	value, _ = url.QueryUnescape(value)
+	if !hasValue {
+		return url.QueryEscape(key)
+	}
+	return url.QueryEscape(key) + "=" + url.QueryEscape(value)
+}
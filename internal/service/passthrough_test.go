@@ -0,0 +1,42 @@
+package service
+
+import "testing"
+
+func TestPassthroughQuery(t *testing.T) {
+	requestWins := &Link{QueryPassthrough: queryRequestWins}
+	linkWins := &Link{QueryPassthrough: queryLinkWins}
+
+	tests := []struct {
+		name  string
+		link  *Link
+		dest  string
+		query string
+		want  string
+	}{
+		{"appended in order", requestWins, "https://example.com/?z=1&a=2", "m=3&b=4", "https://example.com/?z=1&a=2&m=3&b=4"},
+		{"no own query", requestWins, "https://example.com/", "b=2&a=1", "https://example.com/?b=2&a=1"},
+		{"encoding kept", requestWins, "https://example.com/?q=a%20b&s=x+y", "t=%7E&u=a%2Fb", "https://example.com/?q=a%20b&s=x+y&t=%7E&u=a%2Fb"},
+		{"request wins", requestWins, "https://example.com/?a=1&b=2&a=3", "a=9", "https://example.com/?b=2&a=9"},
+		{"link wins", linkWins, "https://example.com/?a=1&b=2", "a=9&c=3", "https://example.com/?a=1&b=2&c=3"},
+		{"escaped key matches", linkWins, "https://example.com/?a%5Bb%5D=1", "a[b]=2", "https://example.com/?a%5Bb%5D=1"},
+		{"repeated keys kept", requestWins, "https://example.com/", "tag=a&tag=b", "https://example.com/?tag=a&tag=b"},
+		{"fragment kept last", requestWins, "https://example.com/?a=1#top", "b=2", "https://example.com/?a=1&b=2#top"},
+		{"unsafe characters escaped", requestWins, "https://example.com/", "a=b#c&d=e f", "https://example.com/?a=b%23c&d=e+f"},
+		{"disabled", &Link{}, "https://example.com/?a=1", "b=2", "https://example.com/?a=1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.link.passthrough(tt.dest, "", tt.query)
+			if err != nil {
+				t.Fatalf("passthrough() = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("passthrough(%q, %q) = %q, want %q", tt.dest, tt.query, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := requestWins.passthrough("https://example.com/", "", "a=%zz"); err == nil {
+		t.Error("passthrough() accepted an invalid query")
+	}
+}
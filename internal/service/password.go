@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "tinyurl/proto/tinyurl/v1"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	MinPasswordLength = 6
+	MaxPasswordLength = 72 // bcrypt ignores anything longer
+
+	// Unlock attempts are counted per link and client IP until one succeeds
+	UnlockMaxAttempts   = 5
+	UnlockAttemptWindow = 15 * time.Minute
+)
+
+const unlockAttemptsPrefix = "unlock_attempts:"
+
+// randomSecret is the signing key used when none is configured, which only
+// works for a single instance.
+func randomSecret() []byte {
+	key := make([]byte, 32)
+	rand.Read(key)
+	return key
+}
+
+func hashPassword(password string) (string, error) {
+	if len(password) < MinPasswordLength || len(password) > MaxPasswordLength {
+		return "", status.Errorf(codes.InvalidArgument, "password must be %d to %d characters long", MinPasswordLength, MaxPasswordLength)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "Failed to hash password: %v", err)
+	}
+	return string(hash), nil
+}
+
+// unlockToken is "<expires>.<signature>". The signature covers the
+// password hash, so changing the password locks the link again.
+func (s *TinyURLService) unlockToken(code string, link *Link, expiresAt time.Time) string {
+	exp := strconv.FormatInt(expiresAt.Unix(), 10)
+	return exp + "." + s.signUnlock(code, link, exp)
+}
+
+func (s *TinyURLService) signUnlock(code string, link *Link, exp string) string {
+	mac := hmac.New(sha256.New, s.UnlockSecret)
+	mac.Write([]byte(code + "." + exp + "." + link.PasswordHash))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *TinyURLService) unlocked(code string, link *Link, token string) bool {
+	exp, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+	expires, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || time.Now().Unix() >= expires {
+		return false
+	}
+	return hmac.Equal([]byte(sig), []byte(s.signUnlock(code, link, exp)))
+}
+
+func passwordRequiredError() error {
+	return errorWithInfo(codes.PermissionDenied, "This link is password protected", ReasonPasswordRequired, nil)
+}
+
+func (s *TinyURLService) UnlockLink(ctx context.Context, req *pb.UnlockLinkRequest) (*pb.UnlockLinkResponse, error) {
+	if req.ShortCode == "" {
+		return nil, status.Error(codes.InvalidArgument, "short_code is required")
+	}
+	if req.Password == "" {
+		return nil, status.Error(codes.InvalidArgument, "password is required")
+	}
+
+	link, err := s.getLink(ctx, req.ShortCode)
+	if err == redis.Nil {
+		return nil, status.Error(codes.NotFound, "URL not found")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "Redis error: %v", err)
+	}
+	if link.PasswordHash == "" {
+		return nil, status.Error(codes.FailedPrecondition, "This link isn't password protected")
+	}
+	if err := s.checkAccess(ctx, link, false); err != nil {
+		return nil, err
+	}
+
+	var ip string
+	if s.ClientIPs != nil {
+		ip = s.ClientIPs.FromContext(ctx)
+	}
+	// Count the attempt before comparing, so concurrent guesses can't all
+	// pass the check while the slow comparison runs
+	key := unlockAttemptsPrefix + req.ShortCode + ":" + ip
+	var attempts *redis.IntCmd
+	var ttl *redis.DurationCmd
+	_, err = s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		attempts = pipe.Incr(ctx, key)
+		pipe.ExpireNX(ctx, key, UnlockAttemptWindow)
+		ttl = pipe.TTL(ctx, key)
+		return nil
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "Redis error: %v", err)
+	}
+	if attempts.Val() > UnlockMaxAttempts {
+		return nil, status.Errorf(codes.ResourceExhausted, "Too many wrong passwords. Try again in %d minutes.", max(int(ttl.Val().Minutes()+0.5), 1))
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(link.PasswordHash), []byte(req.Password)) != nil {
+		return nil, status.Error(codes.PermissionDenied, "Wrong password")
+	}
+	s.rdb.Del(ctx, key)
+
+	expiresAt := time.Now().Add(s.UnlockTTL)
+	return &pb.UnlockLinkResponse{
+		Token:     s.unlockToken(req.ShortCode, link, expiresAt),
+		ExpiresAt: expiresAt.Unix(),
+	}, nil
+}
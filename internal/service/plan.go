@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	PlanAnonymous = "anonymous"
+	PlanFree      = "free"
+	PlanPro       = "pro"
+	PlanInternal  = "internal"
+)
+
+// Plan describes what a caller is allowed to do when creating links.
+// A MaxExpiryHours of 0 means links never expire.
+type Plan struct {
+	Name                   string `json:"name"`
+	MaxExpiryHours         int    `json:"max_expiry_hours"`
+	CustomAlias            bool   `json:"custom_alias"`
+	AnalyticsRetentionDays int    `json:"analytics_retention_days"`
+	RateLimit              int    `json:"rate_limit"`        // requests per window
+	RateLimitWindowSec     int    `json:"rate_limit_window"` // in seconds
+}
+
+func (p Plan) RateLimitWindow() time.Duration {
+	return time.Duration(p.RateLimitWindowSec) * time.Second
+}
+
+func (p Plan) AnalyticsRetention() time.Duration {
+	return time.Duration(p.AnalyticsRetentionDays) * 24 * time.Hour
+}
+
+// Describe returns a short human readable summary of the plan limits,
+// used in API responses.
+func (p Plan) Describe() string {
+	expiry := "links that never expire"
+	if p.MaxExpiryHours > 0 {
+		expiry = fmt.Sprintf("links up to %d hours", p.MaxExpiryHours)
+	}
+	alias := "no custom aliases"
+	if p.CustomAlias {
+		alias = "custom aliases"
+	}
+	return fmt.Sprintf("%s plan allows %s, %s, %d days of analytics and %d requests per %s.",
+		strings.ToUpper(p.Name[:1])+p.Name[1:], expiry, alias, p.AnalyticsRetentionDays, p.RateLimit, p.RateLimitWindow())
+}
+
+// APIKey assigns a plan to a key, either directly or through its workspace.
+// Admin keys may also call the admin RPCs.
+type APIKey struct {
+	Plan      string `json:"plan,omitempty"`
+	Workspace string `json:"workspace,omitempty"`
+	Admin     bool   `json:"admin,omitempty"`
+}
+
+// Caller is the resolved identity behind a request.
+type Caller struct {
+	Plan      Plan
+	APIKey    string
+	Workspace string
+	Admin     bool
+}
+
+func (c Caller) Authenticated() bool {
+	return c.APIKey != ""
+}
+
+// Owner identifies who created a link: the workspace, or a short hash of the
+// API key so the key itself is never stored. Anonymous callers have none.
+func (c Caller) Owner() string {
+	if c.Workspace != "" {
+		return c.Workspace
+	}
+	if c.APIKey != "" {
+		sum := sha256.Sum256([]byte(c.APIKey))
+		return "key:" + hex.EncodeToString(sum[:4])
+	}
+	return ""
+}
+
+// canManage reports whether the caller may look at or change a link.
+// Anonymous links can only be managed by admins.
+func (c Caller) canManage(link *Link) bool {
+	return c.Admin || (link.Owner != "" && link.Owner == c.Owner())
+}
+
+// Plans holds the configured plans and the API keys and workspaces they are
+// assigned to.
+type Plans struct {
+	Plans      map[string]Plan
+	APIKeys    map[string]APIKey
+	Workspaces map[string]string // workspace -> plan
+}
+
+// DefaultPlans returns the built-in plans. anonymousExpiryHours and the rate limit
+// settings keep the behaviour of the old single "exclusive link" tier.
+func DefaultPlans(anonymousExpiryHours, rateLimit int, rateWindow time.Duration) *Plans {
+	window := int(rateWindow / time.Second)
+	return &Plans{
+		Plans: map[string]Plan{
+			PlanAnonymous: {Name: PlanAnonymous, MaxExpiryHours: anonymousExpiryHours, CustomAlias: true, AnalyticsRetentionDays: 1, RateLimit: rateLimit, RateLimitWindowSec: window},
+			PlanFree:      {Name: PlanFree, MaxExpiryHours: 24 * 7, CustomAlias: true, AnalyticsRetentionDays: 30, RateLimit: 30, RateLimitWindowSec: 60},
+			PlanPro:       {Name: PlanPro, MaxExpiryHours: 24 * 365, CustomAlias: true, AnalyticsRetentionDays: 365, RateLimit: 120, RateLimitWindowSec: 60},
+			PlanInternal:  {Name: PlanInternal, MaxExpiryHours: 0, CustomAlias: true, AnalyticsRetentionDays: 730, RateLimit: 600, RateLimitWindowSec: 60},
+		},
+		APIKeys:    map[string]APIKey{},
+		Workspaces: map[string]string{},
+	}
+}
+
+// LoadFile reads a JSON plan file on top of the current plans. Plans in the file
+// replace the built-in plan with the same name.
+func (p *Plans) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var file struct {
+		Plans      []Plan            `json:"plans"`
+		APIKeys    map[string]APIKey `json:"api_keys"`
+		Workspaces map[string]string `json:"workspaces"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	for _, plan := range file.Plans {
+		if plan.Name == "" {
+			return fmt.Errorf("parse %s: plan without name", path)
+		}
+		p.Plans[plan.Name] = plan
+	}
+	for key, apiKey := range file.APIKeys {
+		p.APIKeys[key] = apiKey
+	}
+	for workspace, plan := range file.Workspaces {
+		p.Workspaces[workspace] = plan
+	}
+
+	// Make sure every reference points to an existing plan
+	for key, apiKey := range p.APIKeys {
+		if apiKey.Plan != "" {
+			if _, ok := p.Plans[apiKey.Plan]; !ok {
+				return fmt.Errorf("api key %s...: unknown plan %q", key[:min(4, len(key))], apiKey.Plan)
+			}
+		}
+	}
+	for workspace, plan := range p.Workspaces {
+		if _, ok := p.Plans[plan]; !ok {
+			return fmt.Errorf("workspace %s: unknown plan %q", workspace, plan)
+		}
+	}
+	return nil
+}
+
+// Resolve maps an API key to a caller. An empty key is an anonymous caller.
+func (p *Plans) Resolve(apiKey string) (Caller, error) {
+	if apiKey == "" {
+		return Caller{Plan: p.Plans[PlanAnonymous]}, nil
+	}
+
+	key, ok := p.APIKeys[apiKey]
+	if !ok {
+		return Caller{}, status.Error(codes.Unauthenticated, "Invalid API key")
+	}
+
+	planName := key.Plan
+	if planName == "" {
+		planName = p.Workspaces[key.Workspace]
+	}
+	if planName == "" {
+		planName = PlanFree
+	}
+
+	return Caller{
+		Plan:      p.Plans[planName],
+		APIKey:    apiKey,
+		Workspace: key.Workspace,
+		Admin:     key.Admin,
+	}, nil
+}
+
+// CallerFromContext resolves the caller from the "authorization: Bearer <key>"
+// gRPC metadata. The gateway forwards the HTTP Authorization header as is.
+func (p *Plans) CallerFromContext(ctx context.Context) (Caller, error) {
+	return p.Resolve(APIKeyFromContext(ctx))
+}
+
+func APIKeyFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if v := md.Get("authorization"); len(v) > 0 {
+		return BearerToken(v[0])
+	}
+	return ""
+}
+
+// BearerToken extracts the token from an "Authorization: Bearer <token>" value.
+func BearerToken(header string) string {
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+
+	"tinyurl/internal/policy"
+	pb "tinyurl/proto/tinyurl/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// requireAdmin only lets callers with an admin API key through.
+func (s *TinyURLService) requireAdmin(ctx context.Context) error {
+	caller, err := s.plans.CallerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if !caller.Authenticated() {
+		return status.Error(codes.Unauthenticated, "Admin API key required")
+	}
+	if !caller.Admin {
+		return status.Error(codes.PermissionDenied, "Admin API key required")
+	}
+	return nil
+}
+
+// checkDomain consults the domain policy for the destination of a link.
+func (s *TinyURLService) checkDomain(longURL string) error {
+	decision := s.policies.Check(hostOf(longURL))
+	if !decision.Allowed {
+		return status.Errorf(codes.PermissionDenied, "Destination domain is not allowed (%s)", decision.Reason())
+	}
+	return nil
+}
+
+func (s *TinyURLService) ReloadPolicies(ctx context.Context, req *pb.ReloadPoliciesRequest) (*pb.PolicyStatus, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := s.policies.Reload(); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "Failed to reload policies: %v", err)
+	}
+	return policyStatusToProto(s.policies.Status()), nil
+}
+
+func (s *TinyURLService) GetPolicyStatus(ctx context.Context, req *pb.GetPolicyStatusRequest) (*pb.PolicyStatus, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return policyStatusToProto(s.policies.Status()), nil
+}
+
+func policyStatusToProto(st policy.Status) *pb.PolicyStatus {
+	resp := &pb.PolicyStatus{
+		Path:          st.Path,
+		AllowRules:    int32(st.AllowRules),
+		BlockRules:    int32(st.BlockRules),
+		DefaultAction: st.DefaultAction,
+	}
+	if !st.LoadedAt.IsZero() {
+		resp.LoadedAt = st.LoadedAt.Unix()
+	}
+	if st.LastError != nil {
+		resp.LastError = st.LastError.Error()
+	}
+	return resp
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "tinyurl/proto/tinyurl/v1"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ProofOfWork issues and verifies hashcash-style challenges. A challenge is
+// "<id>.<difficulty>.<expires>.<signature>", and a solution is any nonce for
+// which sha256(challenge + ":" + nonce) starts with difficulty zero bits.
+type ProofOfWork struct {
+	rdb    *redis.Client
+	secret []byte
+
+	// Difficulty grows by one bit every time the anonymous link volume of
+	// the last minute doubles past VolumeThreshold.
+	BaseDifficulty  int
+	MaxDifficulty   int
+	VolumeThreshold int
+	TTL             time.Duration
+}
+
+// NewProofOfWork returns a challenge issuer signing with secret. An empty
+// secret is replaced by a random one, which only works for a single instance.
+func NewProofOfWork(rdb *redis.Client, secret string) *ProofOfWork {
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		rand.Read(key)
+	}
+	return &ProofOfWork{
+		rdb:             rdb,
+		secret:          key,
+		BaseDifficulty:  16,
+		MaxDifficulty:   22,
+		VolumeThreshold: 30,
+		TTL:             5 * time.Minute,
+	}
+}
+
+// Issue creates a new signed challenge sized to the recent volume.
+func (p *ProofOfWork) Issue(ctx context.Context) (challenge string, difficulty int, expiresAt time.Time) {
+	id := make([]byte, 16)
+	rand.Read(id)
+
+	difficulty = p.difficulty(ctx)
+	expiresAt = time.Now().Add(p.TTL)
+	payload := fmt.Sprintf("%s.%d.%d", hex.EncodeToString(id), difficulty, expiresAt.Unix())
+	return payload + "." + p.sign(payload), difficulty, expiresAt
+}
+
+// Verify checks the signature, expiry and work of a solved challenge, and
+// makes sure it is only ever redeemed once. Callers that fail to do what
+// the challenge was for should Release it.
+func (p *ProofOfWork) Verify(ctx context.Context, challenge, nonce string) error {
+	if challenge == "" || nonce == "" {
+		return status.Error(codes.FailedPrecondition, "Proof of work required. Request a challenge from /v1/challenge")
+	}
+
+	parts := strings.Split(challenge, ".")
+	if len(parts) != 4 {
+		return status.Error(codes.InvalidArgument, "Malformed proof of work challenge")
+	}
+	payload := strings.Join(parts[:3], ".")
+	if !hmac.Equal([]byte(p.sign(payload)), []byte(parts[3])) {
+		return status.Error(codes.InvalidArgument, "Invalid proof of work challenge")
+	}
+
+	difficulty, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "Malformed proof of work challenge")
+	}
+	expires, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "Malformed proof of work challenge")
+	}
+	ttl := time.Until(time.Unix(expires, 0))
+	if ttl <= 0 {
+		return status.Error(codes.FailedPrecondition, "Proof of work challenge expired. Request a new one")
+	}
+
+	sum := sha256.Sum256([]byte(challenge + ":" + nonce))
+	if leadingZeroBits(sum[:]) < difficulty {
+		return status.Error(codes.InvalidArgument, "Proof of work solution is not valid")
+	}
+
+	ok, err := p.rdb.SetNX(ctx, "pow_used:"+parts[0], 1, ttl).Result()
+	if err != nil {
+		return status.Errorf(codes.Internal, "Redis error: %v", err)
+	}
+	if !ok {
+		return status.Error(codes.FailedPrecondition, "Proof of work challenge already used. Request a new one")
+	}
+	return nil
+}
+
+// Release makes a verified challenge usable again, for when the request
+// it was redeemed for failed.
+func (p *ProofOfWork) Release(ctx context.Context, challenge string) {
+	id, _, _ := strings.Cut(challenge, ".")
+	if err := p.rdb.Del(ctx, "pow_used:"+id).Err(); err != nil {
+		fmt.Println("Failed to release proof of work challenge:", err)
+	}
+}
+
+// Record counts an anonymous link creation towards the adaptive difficulty.
+func (p *ProofOfWork) Record(ctx context.Context) {
+	key := fmt.Sprintf("pow_volume:%d", time.Now().Unix()/60)
+	_, err := p.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, 2*time.Minute)
+		return nil
+	})
+	if err != nil {
+		fmt.Println("Failed to record proof of work volume:", err)
+	}
+}
+
+func (p *ProofOfWork) difficulty(ctx context.Context) int {
+	minute := time.Now().Unix() / 60
+	counts, err := p.rdb.MGet(ctx, fmt.Sprintf("pow_volume:%d", minute), fmt.Sprintf("pow_volume:%d", minute-1)).Result()
+	if err != nil {
+		return p.MaxDifficulty
+	}
+
+	volume := 0
+	for _, c := range counts {
+		if s, ok := c.(string); ok {
+			n, _ := strconv.Atoi(s)
+			volume += n
+		}
+	}
+
+	difficulty := p.BaseDifficulty
+	for v := volume; v > p.VolumeThreshold && difficulty < p.MaxDifficulty; v /= 2 {
+		difficulty++
+	}
+	return difficulty
+}
+
+func (p *ProofOfWork) sign(payload string) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func leadingZeroBits(b []byte) int {
+	n := 0
+	for _, c := range b {
+		if c != 0 {
+			return n + bits.LeadingZeros8(c)
+		}
+		n += 8
+	}
+	return n
+}
+
+func (s *TinyURLService) GetChallenge(ctx context.Context, req *pb.GetChallengeRequest) (*pb.GetChallengeResponse, error) {
+	if s.pow == nil {
+		return &pb.GetChallengeResponse{Required: false}, nil
+	}
+
+	challenge, difficulty, expiresAt := s.pow.Issue(ctx)
+	return &pb.GetChallengeResponse{
+		Required:   true,
+		Challenge:  challenge,
+		Difficulty: int32(difficulty),
+		ExpiresAt:  expiresAt.Unix(),
+	}, nil
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// redisClient connects to the Redis at REDIS_ADDR, or skips the test when
+// there is none. Tests use a database of their own and empty it afterwards.
+func redisClient(t *testing.T) *redis.Client {
+	t.Helper()
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		addr = "localhost:6379"
+	}
+	rdb := redis.NewClient(&redis.Options{Addr: addr, DB: 15})
+	if err := rdb.Ping(context.Background()).Err(); err != nil {
+		t.Skipf("Redis not available at %s: %v", addr, err)
+	}
+	t.Cleanup(func() {
+		rdb.FlushDB(context.Background())
+		rdb.Close()
+	})
+	return rdb
+}
+
+// challenge signs a challenge like Issue does, with a chosen difficulty
+// and expiry.
+func challenge(p *ProofOfWork, id string, difficulty int, expiresAt time.Time) string {
+	payload := fmt.Sprintf("%s.%d.%d", id, difficulty, expiresAt.Unix())
+	return payload + "." + p.sign(payload)
+}
+
+func solve(challenge string, difficulty int) string {
+	for n := 0; ; n++ {
+		nonce := strconv.Itoa(n)
+		sum := sha256.Sum256([]byte(challenge + ":" + nonce))
+		if leadingZeroBits(sum[:]) >= difficulty {
+			return nonce
+		}
+	}
+}
+
+// unsolve finds a nonce that doesn't meet the difficulty.
+func unsolve(challenge string, difficulty int) string {
+	for n := 0; ; n++ {
+		nonce := strconv.Itoa(n)
+		sum := sha256.Sum256([]byte(challenge + ":" + nonce))
+		if leadingZeroBits(sum[:]) < difficulty {
+			return nonce
+		}
+	}
+}
+
+func TestProofOfWorkVerifyRejects(t *testing.T) {
+	// None of these get as far as Redis
+	p := NewProofOfWork(nil, "secret")
+	other := NewProofOfWork(nil, "other secret")
+	future := time.Now().Add(time.Minute)
+
+	valid := challenge(p, "aaaa", 8, future)
+	expired := challenge(p, "bbbb", 8, time.Now().Add(-time.Second))
+	foreign := challenge(other, "cccc", 8, future)
+	parts := strings.Split(valid, ".")
+	easier := strings.Join([]string{parts[0], "1", parts[2], parts[3]}, ".")
+	later := strings.Join([]string{parts[0], parts[1], strconv.FormatInt(future.Add(time.Hour).Unix(), 10), parts[3]}, ".")
+
+	tests := []struct {
+		name      string
+		challenge string
+		nonce     string
+		want      codes.Code
+	}{
+		{"missing challenge", "", "1", codes.FailedPrecondition},
+		{"missing nonce", valid, "", codes.FailedPrecondition},
+		{"malformed", "aaaa.8", "1", codes.InvalidArgument},
+		{"other secret", foreign, solve(foreign, 8), codes.InvalidArgument},
+		{"lowered difficulty", easier, solve(easier, 1), codes.InvalidArgument},
+		{"extended expiry", later, solve(later, 8), codes.InvalidArgument},
+		{"expired", expired, solve(expired, 8), codes.FailedPrecondition},
+		{"not enough work", valid, unsolve(valid, 8), codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := p.Verify(context.Background(), tt.challenge, tt.nonce)
+			if status.Code(err) != tt.want {
+				t.Errorf("Verify() = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestProofOfWorkVerifyOnce(t *testing.T) {
+	p := NewProofOfWork(redisClient(t), "secret")
+	c := challenge(p, "dddd", 8, time.Now().Add(time.Minute))
+	nonce := solve(c, 8)
+
+	if err := p.Verify(context.Background(), c, nonce); err != nil {
+		t.Fatalf("Verify() of a solved challenge = %v", err)
+	}
+	if err := p.Verify(context.Background(), c, nonce); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("second Verify() = %v, want FailedPrecondition", err)
+	}
+	if err := p.Verify(context.Background(), c, solve(c+"x", 8)); err == nil {
+		t.Error("challenge redeemed again with another nonce")
+	}
+
+	p.Release(context.Background(), c)
+	if err := p.Verify(context.Background(), c, nonce); err != nil {
+		t.Errorf("Verify() after Release() = %v", err)
+	}
+}
+
+func TestProofOfWorkIssue(t *testing.T) {
+	p := NewProofOfWork(redisClient(t), "secret")
+	c, difficulty, expiresAt := p.Issue(context.Background())
+	if difficulty != p.BaseDifficulty {
+		t.Errorf("difficulty = %d without any volume, want %d", difficulty, p.BaseDifficulty)
+	}
+	if time.Until(expiresAt) <= 0 || time.Until(expiresAt) > p.TTL {
+		t.Errorf("expires at %s, want within %s", expiresAt, p.TTL)
+	}
+	if parts := strings.Split(c, "."); len(parts) != 4 || parts[1] != strconv.Itoa(difficulty) {
+		t.Errorf("challenge %q doesn't carry difficulty %d", c, difficulty)
+	}
+
+	// Every doubling of the volume past the threshold adds a bit
+	for range 4 * p.VolumeThreshold {
+		p.Record(context.Background())
+	}
+	if _, difficulty, _ := p.Issue(context.Background()); difficulty != p.BaseDifficulty+2 {
+		t.Errorf("difficulty = %d at four times the threshold, want %d", difficulty, p.BaseDifficulty+2)
+	}
+}
+
+func TestLeadingZeroBits(t *testing.T) {
+	tests := []struct {
+		in   []byte
+		want int
+	}{
+		{[]byte{0x80}, 0},
+		{[]byte{0x01}, 7},
+		{[]byte{0x00, 0xff}, 8},
+		{[]byte{0x00, 0x00, 0x10}, 19},
+		{[]byte{0x00, 0x00}, 16},
+		{nil, 0},
+	}
+	for _, tt := range tests {
+		if got := leadingZeroBits(tt.in); got != tt.want {
+			t.Errorf("leadingZeroBits(%x) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	pb "tinyurl/proto/tinyurl/v1"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PreviewLink goes through the same checks as GetOriginal, so a link that
+// wouldn't redirect doesn't preview either, but never counts a click.
+func (s *TinyURLService) PreviewLink(ctx context.Context, req *pb.PreviewLinkRequest) (*pb.LinkPreview, error) {
+	if req.ShortCode == "" {
+		return nil, status.Error(codes.InvalidArgument, "short_code is required")
+	}
+	if s.misses.Has(req.ShortCode) {
+		return nil, status.Error(codes.NotFound, "URL not found")
+	}
+
+	link, err := s.getLink(ctx, req.ShortCode)
+	if err == redis.Nil {
+		if t, err := s.getTombstone(ctx, req.ShortCode); err != nil {
+			return nil, status.Errorf(codes.Unavailable, "Redis error: %v", err)
+		} else if t != nil {
+			return nil, expiredError(t)
+		}
+		s.misses.Add(req.ShortCode)
+		return nil, status.Error(codes.NotFound, "URL not found")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "Redis error: %v", err)
+	}
+
+	if link.Disabled {
+		return nil, disabledError(link)
+	}
+	if err := s.checkAccess(ctx, link, false); err != nil {
+		return nil, err
+	}
+	if time.Now().Before(link.NotBefore) {
+		return nil, notActiveError(link.NotBefore.In(link.location(s.TimeZone)))
+	}
+
+	preview := &pb.LinkPreview{
+		ShortCode:         req.ShortCode,
+		Safety:            pb.LinkSafety_LINK_SAFETY_OK,
+		Broken:            link.Health != nil && link.Health.Broken,
+		Targeted:          len(link.Rules)+len(link.Schedules)+len(link.Variants) > 0,
+		PasswordProtected: link.PasswordHash != "",
+		Limited:           link.MaxClicks > 0,
+	}
+	if !link.CreatedAt.IsZero() {
+		preview.CreatedAt = link.CreatedAt.Unix()
+	}
+	clicks, err := s.rdb.HGet(ctx, statsKey(req.ShortCode, totalDate), clicksField).Int64()
+	if err != nil && err != redis.Nil {
+		return nil, status.Errorf(codes.Unavailable, "Redis error: %v", err)
+	}
+	preview.Clicks = clicks
+
+	if err := s.checkDomain(link.LongURL); err != nil {
+		preview.Safety = pb.LinkSafety_LINK_SAFETY_BLOCKED
+		preview.Warning = status.Convert(err).Message()
+	} else if _, ok := s.threats.Match(link.LongURL); ok || link.Flagged != "" {
+		preview.Safety = pb.LinkSafety_LINK_SAFETY_FLAGGED
+		preview.Warning = threatWarning
+	}
+
+	// The password is what keeps the destination secret, and a link with a
+	// click limit may only be read by using a click
+	if link.PasswordHash == "" && link.MaxClicks == 0 {
+		preview.LongUrl = link.LongURL
+		preview.Domain = hostOf(link.LongURL)
+	}
+	return preview, nil
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	pb "tinyurl/proto/tinyurl/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// redirectTypes maps the API values to what is stored on a link. Links
+// without a redirect type use 303.
+var redirectTypes = map[pb.RedirectType]string{
+	pb.RedirectType_REDIRECT_TYPE_301:          "301",
+	pb.RedirectType_REDIRECT_TYPE_302:          "302",
+	pb.RedirectType_REDIRECT_TYPE_303:          "303",
+	pb.RedirectType_REDIRECT_TYPE_307:          "307",
+	pb.RedirectType_REDIRECT_TYPE_308:          "308",
+	pb.RedirectType_REDIRECT_TYPE_META_REFRESH: "meta_refresh",
+	pb.RedirectType_REDIRECT_TYPE_JAVASCRIPT:   "javascript",
+}
+
+func redirectTypeFromProto(t pb.RedirectType) (string, error) {
+	if t == pb.RedirectType_REDIRECT_TYPE_UNSPECIFIED {
+		return "", nil
+	}
+	v, ok := redirectTypes[t]
+	if !ok {
+		return "", status.Error(codes.InvalidArgument, "Unknown redirect_type")
+	}
+	return v, nil
+}
+
+func redirectTypeToProto(v string) pb.RedirectType {
+	for t, s := range redirectTypes {
+		if s == v {
+			return t
+		}
+	}
+	return pb.RedirectType_REDIRECT_TYPE_303
+}
+
+// permanentRedirect reports whether browsers may cache the redirect.
+func permanentRedirect(v string) bool {
+	return v == "301" || v == "308"
+}
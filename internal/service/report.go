@@ -0,0 +1,376 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "tinyurl/proto/tinyurl/v1"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Reports are stored as JSON under report:<id>. The moderation queue is a
+// sorted set of open report IDs scored by creation time, and each reported
+// link keeps the set of its open reports so they can be closed together.
+const (
+	reportKeyPrefix     = "report:"
+	reportQueueOpen     = "reports:open"
+	reportQueueResolved = "reports:resolved"
+	reportLinkPrefix    = "reports:link:"
+
+	// Resolved reports are kept this long for reference
+	reportRetention = 90 * 24 * time.Hour
+
+	maxReportDetails = 2000
+	maxReportContact = 254
+)
+
+var reportReasons = map[string]bool{
+	"phishing": true,
+	"malware":  true,
+	"spam":     true,
+	"illegal":  true,
+	"other":    true,
+}
+
+const (
+	reportOpen     = "open"
+	reportResolved = "resolved"
+)
+
+type report struct {
+	ID         string    `json:"id"`
+	ShortCode  string    `json:"short_code"`
+	LongURL    string    `json:"long_url"`
+	Reason     string    `json:"reason"`
+	Details    string    `json:"details,omitempty"`
+	Contact    string    `json:"contact,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	Status     string    `json:"status"`
+	Resolution string    `json:"resolution,omitempty"`
+	Note       string    `json:"note,omitempty"`
+	ResolvedAt time.Time `json:"resolved_at,omitzero"`
+}
+
+func (r *report) toProto() *pb.Report {
+	resp := &pb.Report{
+		Id:         r.ID,
+		ShortCode:  r.ShortCode,
+		LongUrl:    r.LongURL,
+		Reason:     r.Reason,
+		Details:    r.Details,
+		Contact:    r.Contact,
+		CreatedAt:  r.CreatedAt.Unix(),
+		Status:     r.Status,
+		Resolution: r.Resolution,
+		Note:       r.Note,
+	}
+	if !r.ResolvedAt.IsZero() {
+		resp.ResolvedAt = r.ResolvedAt.Unix()
+	}
+	return resp
+}
+
+func (s *TinyURLService) ReportLink(ctx context.Context, req *pb.ReportLinkRequest) (*pb.ReportLinkResponse, error) {
+	if req.ShortCode == "" {
+		return nil, status.Error(codes.InvalidArgument, "short_code is required")
+	}
+	reason := strings.ToLower(strings.TrimSpace(req.Reason))
+	if !reportReasons[reason] {
+		return nil, status.Error(codes.InvalidArgument, "reason must be one of phishing, malware, spam, illegal or other")
+	}
+	if len(req.Details) > maxReportDetails {
+		return nil, status.Errorf(codes.InvalidArgument, "details must be at most %d characters", maxReportDetails)
+	}
+	if len(req.Contact) > maxReportContact {
+		return nil, status.Errorf(codes.InvalidArgument, "contact must be at most %d characters", maxReportContact)
+	}
+
+	link, err := s.getLink(ctx, req.ShortCode)
+	if err == redis.Nil {
+		return nil, status.Error(codes.NotFound, "URL not found")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "Redis error: %v", err)
+	}
+	if link.Disabled {
+		return &pb.ReportLinkResponse{Message: "Thanks for your report. This link has already been disabled."}, nil
+	}
+
+	id := make([]byte, 8)
+	rand.Read(id)
+	r := &report{
+		ID:        hex.EncodeToString(id),
+		ShortCode: req.ShortCode,
+		LongURL:   link.LongURL,
+		Reason:    reason,
+		Details:   strings.TrimSpace(req.Details),
+		Contact:   strings.TrimSpace(req.Contact),
+		CreatedAt: time.Now(),
+		Status:    reportOpen,
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to encode report: %v", err)
+	}
+
+	_, err = s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, reportKeyPrefix+r.ID, data, 0)
+		pipe.ZAdd(ctx, reportQueueOpen, redis.Z{Score: float64(r.CreatedAt.Unix()), Member: r.ID})
+		pipe.SAdd(ctx, reportLinkPrefix+r.ShortCode, r.ID)
+		return nil
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to save report: %v", err)
+	}
+
+	fmt.Printf("Link %s reported for %s (report %s)\n", r.ShortCode, r.Reason, r.ID)
+	return &pb.ReportLinkResponse{
+		Id:      r.ID,
+		Message: "Thanks for your report. Our moderators will review it.",
+	}, nil
+}
+
+func (s *TinyURLService) ListReports(ctx context.Context, req *pb.ListReportsRequest) (*pb.ListReportsResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	queue := reportQueueOpen
+	switch req.Status {
+	case "", reportOpen:
+	case reportResolved:
+		queue = reportQueueResolved
+	default:
+		return nil, status.Error(codes.InvalidArgument, "status must be open or resolved")
+	}
+	limit := int64(req.Limit)
+	if limit <= 0 {
+		limit = 50
+	}
+	limit = min(limit, 500)
+	offset := max(int64(req.Offset), 0)
+
+	total, err := s.rdb.ZCard(ctx, queue).Result()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Redis error: %v", err)
+	}
+	ids, err := s.rdb.ZRevRange(ctx, queue, offset, offset+limit-1).Result()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Redis error: %v", err)
+	}
+
+	resp := &pb.ListReportsResponse{Total: total}
+	if len(ids) == 0 {
+		return resp, nil
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = reportKeyPrefix + id
+	}
+	values, err := s.rdb.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Redis error: %v", err)
+	}
+	for _, v := range values {
+		data, ok := v.(string)
+		if !ok {
+			continue // Expired
+		}
+		var r report
+		if err := json.Unmarshal([]byte(data), &r); err != nil {
+			continue
+		}
+		resp.Reports = append(resp.Reports, r.toProto())
+	}
+	return resp, nil
+}
+
+func (s *TinyURLService) ResolveReport(ctx context.Context, req *pb.ResolveReportRequest) (*pb.Report, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	r, err := s.getReport(ctx, req.Id)
+	if err == redis.Nil {
+		return nil, status.Error(codes.NotFound, "Report not found")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "Redis error: %v", err)
+	}
+	if r.Status == reportResolved {
+		return nil, status.Error(codes.FailedPrecondition, "Report is already resolved")
+	}
+
+	switch req.Action {
+	case pb.ReportAction_REPORT_ACTION_DISMISS:
+		err = s.resolveReport(ctx, r, "dismissed", req.Note)
+	case pb.ReportAction_REPORT_ACTION_DISABLE:
+		reason := req.Note
+		if reason == "" {
+			reason = fmt.Sprintf("Reported for %s", r.Reason)
+		}
+		if err := s.disableLink(ctx, r.ShortCode, reason, req.Legal); err != nil {
+			return nil, err
+		}
+		err = s.resolveLinkReports(ctx, r.ShortCode, "disabled", req.Note)
+	case pb.ReportAction_REPORT_ACTION_DELETE:
+		if err := s.deleteLink(ctx, r.ShortCode); err != nil && status.Code(err) != codes.NotFound {
+			return nil, err
+		}
+		err = s.resolveLinkReports(ctx, r.ShortCode, "deleted", req.Note)
+	default:
+		return nil, status.Error(codes.InvalidArgument, "action is required")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to resolve report: %v", err)
+	}
+
+	r, err = s.getReport(ctx, req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Redis error: %v", err)
+	}
+	return r.toProto(), nil
+}
+
+func (s *TinyURLService) DisableLink(ctx context.Context, req *pb.DisableLinkRequest) (*pb.DisableLinkResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := s.disableLink(ctx, req.ShortCode, req.Reason, req.Legal); err != nil {
+		return nil, err
+	}
+	if err := s.resolveLinkReports(ctx, req.ShortCode, "disabled", req.Reason); err != nil {
+		fmt.Printf("Failed to resolve reports for %s: %v\n", req.ShortCode, err)
+	}
+	return &pb.DisableLinkResponse{}, nil
+}
+
+func (s *TinyURLService) DeleteLink(ctx context.Context, req *pb.DeleteLinkRequest) (*pb.DeleteLinkResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := s.deleteLink(ctx, req.ShortCode); err != nil {
+		return nil, err
+	}
+	if err := s.resolveLinkReports(ctx, req.ShortCode, "deleted", ""); err != nil {
+		fmt.Printf("Failed to resolve reports for %s: %v\n", req.ShortCode, err)
+	}
+	return &pb.DeleteLinkResponse{}, nil
+}
+
+func (s *TinyURLService) disableLink(ctx context.Context, code, reason string, legal bool) error {
+	if code == "" {
+		return status.Error(codes.InvalidArgument, "short_code is required")
+	}
+	link, err := s.getLink(ctx, code)
+	if err == redis.Nil {
+		return status.Error(codes.NotFound, "URL not found")
+	} else if err != nil {
+		return status.Errorf(codes.Internal, "Redis error: %v", err)
+	}
+
+	link.Disabled = true
+	link.DisabledReason = reason
+	link.DisabledLegal = legal
+	if err := s.updateLink(ctx, code, link); err != nil {
+		return status.Errorf(codes.Internal, "Failed to disable link: %v", err)
+	}
+	fmt.Printf("Link %s disabled: %s\n", code, reason)
+	return nil
+}
+
+func (s *TinyURLService) deleteLink(ctx context.Context, code string) error {
+	if code == "" {
+		return status.Error(codes.InvalidArgument, "short_code is required")
+	}
+	link, err := s.getLink(ctx, code)
+	if err == redis.Nil {
+		return status.Error(codes.NotFound, "URL not found")
+	} else if err != nil {
+		return status.Errorf(codes.Internal, "Redis error: %v", err)
+	}
+
+	var del *redis.IntCmd
+	_, err = s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		var err error
+		del, err = s.buryLink(ctx, pipe, code, link)
+		return err
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "Redis error: %v", err)
+	}
+	if del.Val() == 0 {
+		return status.Error(codes.NotFound, "URL not found")
+	}
+	fmt.Printf("Link %s deleted\n", code)
+	return nil
+}
+
+// getReport loads a report, returning redis.Nil when it doesn't exist.
+func (s *TinyURLService) getReport(ctx context.Context, id string) (*report, error) {
+	if id == "" {
+		return nil, redis.Nil
+	}
+	data, err := s.rdb.Get(ctx, reportKeyPrefix+id).Result()
+	if err != nil {
+		return nil, err
+	}
+	var r report
+	if err := json.Unmarshal([]byte(data), &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// resolveReport moves a report from the open queue to the resolved one,
+// from where it ages out after reportRetention.
+func (s *TinyURLService) resolveReport(ctx context.Context, r *report, resolution, note string) error {
+	r.Status = reportResolved
+	r.Resolution = resolution
+	r.Note = note
+	r.ResolvedAt = time.Now()
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	cutoff := r.ResolvedAt.Add(-reportRetention).Unix()
+	_, err = s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, reportKeyPrefix+r.ID, data, reportRetention)
+		pipe.ZRem(ctx, reportQueueOpen, r.ID)
+		pipe.ZAdd(ctx, reportQueueResolved, redis.Z{Score: float64(r.ResolvedAt.Unix()), Member: r.ID})
+		pipe.ZRemRangeByScore(ctx, reportQueueResolved, "-inf", strconv.FormatInt(cutoff, 10))
+		pipe.SRem(ctx, reportLinkPrefix+r.ShortCode, r.ID)
+		return nil
+	})
+	return err
+}
+
+// resolveLinkReports closes every open report of a link once a moderator
+// acted on it.
+func (s *TinyURLService) resolveLinkReports(ctx context.Context, code, resolution, note string) error {
+	ids, err := s.rdb.SMembers(ctx, reportLinkPrefix+code).Result()
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		r, err := s.getReport(ctx, id)
+		if err == redis.Nil {
+			s.rdb.SRem(ctx, reportLinkPrefix+code, id)
+			continue
+		} else if err != nil {
+			return err
+		}
+		if err := s.resolveReport(ctx, r, resolution, note); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pb "tinyurl/proto/tinyurl/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MaxSchedules is the number of schedule windows a link can have.
+const MaxSchedules = 20
+
+var (
+	clockRe  = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$|^24:00$`)
+	weekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+)
+
+// Schedule sends visitors to URL while it's active. Times and dates are
+// in the link's time zone. A window ending before it starts runs past
+// midnight, and belongs to the day it started on.
+type Schedule struct {
+	Days      []string `json:"days,omitempty"`       // "mon", "tue", ..., every day if empty
+	StartTime string   `json:"start_time,omitempty"` // "HH:MM", the start of the day if empty
+	EndTime   string   `json:"end_time,omitempty"`   // "HH:MM", exclusive, the end of the day if empty
+	StartDate string   `json:"start_date,omitempty"` // "YYYY-MM-DD", inclusive
+	EndDate   string   `json:"end_date,omitempty"`   // "YYYY-MM-DD", inclusive
+	URL       string   `json:"url"`
+}
+
+func minuteOfDay(clock string, dflt int) int {
+	if clock == "" {
+		return dflt
+	}
+	h, _ := strconv.Atoi(clock[:2])
+	m, _ := strconv.Atoi(clock[3:])
+	return h*60 + m
+}
+
+func (sc *Schedule) active(t time.Time) bool {
+	start := minuteOfDay(sc.StartTime, 0)
+	end := minuteOfDay(sc.EndTime, 24*60)
+	now := t.Hour()*60 + t.Minute()
+
+	day := t
+	switch {
+	case start < end:
+		if now < start || now >= end {
+			return false
+		}
+	case now >= start:
+	case now < end:
+		day = t.AddDate(0, 0, -1)
+	default:
+		return false
+	}
+
+	if len(sc.Days) > 0 && !slices.Contains(sc.Days, weekdays[day.Weekday()]) {
+		return false
+	}
+	date := day.Format(time.DateOnly)
+	return (sc.StartDate == "" || date >= sc.StartDate) && (sc.EndDate == "" || date <= sc.EndDate)
+}
+
+// location is the time zone schedules of the link are read in.
+func (l *Link) location(dflt *time.Location) *time.Location {
+	if l.TimeZone != "" {
+		if loc, err := loadLocation(l.TimeZone); err == nil {
+			return loc
+		}
+	}
+	return dflt
+}
+
+// locations caches loaded time zones by name. LoadLocation reads the zone
+// database on every call, which is too slow for every redirect.
+var locations sync.Map
+
+// loadLocation is time.LoadLocation with a cache. Only names that loaded
+// are cached, and checkTimeZone keeps others from being stored on links.
+func loadLocation(name string) (*time.Location, error) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locations.Store(name, loc)
+	return loc, nil
+}
+
+// schedule returns the destination of the first active window and its
+// 1-based index, or 0 if none is active.
+func (l *Link) schedule(now time.Time, dflt *time.Location) (string, int) {
+	if len(l.Schedules) == 0 {
+		return "", 0
+	}
+	now = now.In(l.location(dflt))
+	for i := range l.Schedules {
+		if l.Schedules[i].active(now) {
+			return l.Schedules[i].URL, i + 1
+		}
+	}
+	return "", 0
+}
+
+func notActiveError(activeAt time.Time) error {
+	return errorWithInfo(codes.FailedPrecondition, "This link isn't active yet", ReasonLinkNotActive, map[string]string{
+		"active_at": activeAt.Format(time.RFC3339),
+		"time_zone": activeAt.Location().String(),
+	})
+}
+
+func checkTimeZone(name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+	if _, err := loadLocation(name); err != nil || name == "Local" {
+		return "", status.Errorf(codes.InvalidArgument, "Unknown time_zone %q, expected an IANA name like Asia/Jakarta", name)
+	}
+	return name, nil
+}
+
+func checkNotBefore(unix int64) (time.Time, error) {
+	if unix < 0 {
+		return time.Time{}, status.Error(codes.InvalidArgument, "not_before must not be negative")
+	}
+	if unix == 0 {
+		return time.Time{}, nil
+	}
+	return time.Unix(unix, 0).UTC(), nil
+}
+
+func (s *TinyURLService) checkSchedules(params []string, schedules []*pb.Schedule) ([]Schedule, error) {
+	if len(schedules) > MaxSchedules {
+		return nil, status.Errorf(codes.InvalidArgument, "At most %d schedules are allowed", MaxSchedules)
+	}
+
+	var out []Schedule
+	for i, sc := range schedules {
+		schedule := Schedule{
+			StartTime: sc.StartTime,
+			EndTime:   sc.EndTime,
+			StartDate: sc.StartDate,
+			EndDate:   sc.EndDate,
+		}
+		for _, d := range sc.Days {
+			d = strings.ToLower(strings.TrimSpace(d))
+			for wd := range weekdays {
+				if d == strings.ToLower(time.Weekday(wd).String()) {
+					d = weekdays[wd] // "monday" -> "mon"
+				}
+			}
+			if !slices.Contains(weekdays, d) {
+				return nil, status.Errorf(codes.InvalidArgument, "schedules[%d]: unknown day %q, expected one of %s", i, d, strings.Join(weekdays, ", "))
+			}
+			if !slices.Contains(schedule.Days, d) {
+				schedule.Days = append(schedule.Days, d)
+			}
+		}
+
+		for _, clock := range []string{sc.StartTime, sc.EndTime} {
+			if clock != "" && !clockRe.MatchString(clock) {
+				return nil, status.Errorf(codes.InvalidArgument, "schedules[%d]: invalid time %q, expected HH:MM", i, clock)
+			}
+		}
+		if sc.StartTime == "24:00" {
+			return nil, status.Errorf(codes.InvalidArgument, "schedules[%d]: start_time must be before 24:00", i)
+		}
+		if minuteOfDay(sc.StartTime, 0) == minuteOfDay(sc.EndTime, 24*60) {
+			return nil, status.Errorf(codes.InvalidArgument, "schedules[%d]: start_time and end_time must differ", i)
+		}
+
+		for _, date := range []string{sc.StartDate, sc.EndDate} {
+			if _, err := time.Parse(time.DateOnly, date); date != "" && err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "schedules[%d]: invalid date %q, expected YYYY-MM-DD", i, date)
+			}
+		}
+		if sc.StartDate != "" && sc.EndDate != "" && sc.StartDate > sc.EndDate {
+			return nil, status.Errorf(codes.InvalidArgument, "schedules[%d]: start_date must not be after end_date", i)
+		}
+		if len(schedule.Days) == 0 && sc.StartTime == "" && sc.EndTime == "" && sc.StartDate == "" && sc.EndDate == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "schedules[%d]: at least one of days, times or dates is required", i)
+		}
+
+		if sc.Url == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "schedules[%d]: url is required", i)
+		}
+		var err error
+		if schedule.URL, err = s.checkDestination(sc.Url); err != nil {
+			return nil, status.Errorf(status.Code(err), "schedules[%d]: %s", i, status.Convert(err).Message())
+		}
+		if schedule.URL, err = templateDestination(params, schedule.URL); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "schedules[%d]: %v", i, err)
+		}
+		out = append(out, schedule)
+	}
+	return out, nil
+}
+
+func scheduleCounter(schedule int) string {
+	return "schedule:" + strconv.Itoa(schedule)
+}
+
+func schedulesToProto(schedules []Schedule) []*pb.Schedule {
+	var out []*pb.Schedule
+	for _, sc := range schedules {
+		out = append(out, &pb.Schedule{
+			Days:      sc.Days,
+			StartTime: sc.StartTime,
+			EndTime:   sc.EndTime,
+			StartDate: sc.StartDate,
+			EndDate:   sc.EndDate,
+			Url:       sc.URL,
+		})
+	}
+	return out
+}
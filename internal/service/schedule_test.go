@@ -0,0 +1,129 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleActive(t *testing.T) {
+	// 2026-10-16 is a Friday
+	at := func(s string) time.Time {
+		tm, err := time.Parse("2006-01-02 15:04", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm
+	}
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		now      string
+		want     bool
+	}{
+		{"inside window", Schedule{StartTime: "09:00", EndTime: "17:00"}, "2026-10-16 12:00", true},
+		{"start is inclusive", Schedule{StartTime: "09:00", EndTime: "17:00"}, "2026-10-16 09:00", true},
+		{"end is exclusive", Schedule{StartTime: "09:00", EndTime: "17:00"}, "2026-10-16 17:00", false},
+		{"before window", Schedule{StartTime: "09:00", EndTime: "17:00"}, "2026-10-16 08:59", false},
+		{"open start", Schedule{EndTime: "12:00"}, "2026-10-16 00:00", true},
+		{"open end", Schedule{StartTime: "12:00"}, "2026-10-16 23:59", true},
+		{"until 24:00", Schedule{StartTime: "20:00", EndTime: "24:00"}, "2026-10-16 23:59", true},
+		{"matching day", Schedule{Days: []string{"fri"}}, "2026-10-16 12:00", true},
+		{"other day", Schedule{Days: []string{"sat", "sun"}}, "2026-10-16 12:00", false},
+
+		{"overnight before midnight", Schedule{Days: []string{"fri"}, StartTime: "22:00", EndTime: "02:00"}, "2026-10-16 23:00", true},
+		{"overnight after midnight belongs to the day before", Schedule{Days: []string{"fri"}, StartTime: "22:00", EndTime: "02:00"}, "2026-10-17 01:00", true},
+		{"overnight next night", Schedule{Days: []string{"fri"}, StartTime: "22:00", EndTime: "02:00"}, "2026-10-17 23:00", false},
+		{"overnight day before", Schedule{Days: []string{"fri"}, StartTime: "22:00", EndTime: "02:00"}, "2026-10-16 01:00", false},
+		{"overnight gap", Schedule{StartTime: "22:00", EndTime: "02:00"}, "2026-10-16 12:00", false},
+		{"overnight end is exclusive", Schedule{StartTime: "22:00", EndTime: "02:00"}, "2026-10-17 02:00", false},
+
+		{"first date", Schedule{StartDate: "2026-10-16", EndDate: "2026-10-18"}, "2026-10-16 00:00", true},
+		{"last date", Schedule{StartDate: "2026-10-16", EndDate: "2026-10-18"}, "2026-10-18 23:59", true},
+		{"before dates", Schedule{StartDate: "2026-10-16", EndDate: "2026-10-18"}, "2026-10-15 23:59", false},
+		{"after dates", Schedule{StartDate: "2026-10-16", EndDate: "2026-10-18"}, "2026-10-19 00:00", false},
+		{"overnight past the last date", Schedule{EndDate: "2026-10-16", StartTime: "22:00", EndTime: "02:00"}, "2026-10-17 01:00", true},
+		{"overnight before the first date", Schedule{StartDate: "2026-10-17", StartTime: "22:00", EndTime: "02:00"}, "2026-10-17 01:00", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.active(at(tt.now)); got != tt.want {
+				t.Errorf("active(%s) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
+
+// Schedules are read in wall clock time of their time zone, so a window
+// stays put across daylight saving time changes.
+func TestLinkScheduleDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Skip(err)
+	}
+	utc := func(s string) time.Time {
+		tm, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm
+	}
+
+	office := Schedule{StartTime: "09:00", EndTime: "17:00", URL: "https://example.com/office"}
+	// Clocks jump from 02:00 to 03:00 on 2026-03-08 and fall back from
+	// 02:00 to 01:00 on 2026-11-01
+	skipped := Schedule{StartDate: "2026-03-08", EndDate: "2026-03-08", StartTime: "02:00", EndTime: "03:00", URL: "https://example.com/skipped"}
+	repeated := Schedule{StartDate: "2026-11-01", EndDate: "2026-11-01", StartTime: "01:00", EndTime: "02:00", URL: "https://example.com/repeated"}
+
+	tests := []struct {
+		name     string
+		link     Link
+		dflt     *time.Location
+		now      string
+		wantURL  string
+		wantRule int
+	}{
+		{"09:30 EST", Link{TimeZone: "America/New_York", Schedules: []Schedule{office}}, time.UTC, "2026-03-06T14:30:00Z", office.URL, 1},
+		{"08:30 EST", Link{TimeZone: "America/New_York", Schedules: []Schedule{office}}, time.UTC, "2026-03-06T13:30:00Z", "", 0},
+		{"09:30 EDT", Link{TimeZone: "America/New_York", Schedules: []Schedule{office}}, time.UTC, "2026-03-09T13:30:00Z", office.URL, 1},
+		{"16:30 EDT", Link{TimeZone: "America/New_York", Schedules: []Schedule{office}}, time.UTC, "2026-03-09T20:30:00Z", office.URL, 1},
+		{"17:30 EDT", Link{TimeZone: "America/New_York", Schedules: []Schedule{office}}, time.UTC, "2026-03-09T21:30:00Z", "", 0},
+		{"default time zone", Link{Schedules: []Schedule{office}}, ny, "2026-03-09T13:30:00Z", office.URL, 1},
+		{"own time zone wins", Link{TimeZone: "Asia/Jakarta", Schedules: []Schedule{office}}, ny, "2026-03-09T13:30:00Z", "", 0},
+		{"unknown time zone falls back", Link{TimeZone: "Mars/Olympus", Schedules: []Schedule{office}}, jakarta, "2026-03-09T03:00:00Z", office.URL, 1},
+		{"01:59 EST before the gap", Link{TimeZone: "America/New_York", Schedules: []Schedule{skipped}}, time.UTC, "2026-03-08T06:59:00Z", "", 0},
+		{"03:00 EDT after the gap", Link{TimeZone: "America/New_York", Schedules: []Schedule{skipped}}, time.UTC, "2026-03-08T07:00:00Z", "", 0},
+		{"first 01:30", Link{TimeZone: "America/New_York", Schedules: []Schedule{repeated}}, time.UTC, "2026-11-01T05:30:00Z", repeated.URL, 1},
+		{"second 01:30", Link{TimeZone: "America/New_York", Schedules: []Schedule{repeated}}, time.UTC, "2026-11-01T06:30:00Z", repeated.URL, 1},
+		{"02:00 EST", Link{TimeZone: "America/New_York", Schedules: []Schedule{repeated}}, time.UTC, "2026-11-01T07:00:00Z", "", 0},
+		{"first active wins", Link{TimeZone: "America/New_York", Schedules: []Schedule{skipped, office, {StartTime: "00:00", URL: "https://example.com/all-day"}}}, time.UTC, "2026-03-09T13:30:00Z", office.URL, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url, rule := tt.link.schedule(utc(tt.now), tt.dflt)
+			if url != tt.wantURL || rule != tt.wantRule {
+				t.Errorf("schedule(%s) = %q, %d, want %q, %d", tt.now, url, rule, tt.wantURL, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestLoadLocationCaches(t *testing.T) {
+	first, err := loadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second, _ := loadLocation("Europe/Berlin"); second != first {
+		t.Error("loadLocation() loaded the zone again")
+	}
+	if _, err := loadLocation("Mars/Olympus_Mons"); err == nil {
+		t.Error("loadLocation() of an unknown zone succeeded")
+	}
+	if _, ok := locations.Load("Mars/Olympus_Mons"); ok {
+		t.Error("unknown zone cached")
+	}
+}
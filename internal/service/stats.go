@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	pb "tinyurl/proto/tinyurl/v1"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Clicks are counted in one hash per link and day (UTC), "stats:<code>:<date>",
+// which expires with the analytics retention of the link's plan. The total
+// is kept in "stats:<code>:total" until the link goes without clicks for
+// that long.
+const statsPrefix = "stats:"
+
+const (
+	clicksField = "clicks"
+	totalDate   = "total"
+)
+
+func statsKey(code, date string) string {
+	return statsPrefix + code + ":" + date
+}
+
+func statsDate(t time.Time) string {
+	return t.UTC().Format(time.DateOnly)
+}
+
+// recordClick counts a click on a link along with counters like "rule:1".
+// Failures are only logged, they mustn't stop the redirect.
+func (s *TinyURLService) recordClick(ctx context.Context, code string, link *Link, counters ...string) {
+	retention := s.plans.Plans[link.Plan].AnalyticsRetention()
+	if retention <= 0 {
+		return
+	}
+
+	key := statsKey(code, statsDate(time.Now()))
+	_, err := s.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HIncrBy(ctx, key, clicksField, 1)
+		for _, c := range counters {
+			pipe.HIncrBy(ctx, key, c, 1)
+		}
+		// A day is kept for the whole retention after it ends
+		pipe.Expire(ctx, key, retention+24*time.Hour)
+
+		total := statsKey(code, totalDate)
+		pipe.HIncrBy(ctx, total, clicksField, 1)
+		pipe.Expire(ctx, total, retention)
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("Failed to record click on %s: %v\n", code, err)
+	}
+}
+
+func ruleCounter(rule int) string {
+	return fmt.Sprintf("rule:%d", rule)
+}
+
+func (s *TinyURLService) GetLinkStats(ctx context.Context, req *pb.GetLinkStatsRequest) (*pb.LinkStats, error) {
+	if req.ShortCode == "" {
+		return nil, status.Error(codes.InvalidArgument, "short_code is required")
+	}
+	if req.Days < 0 {
+		return nil, status.Error(codes.InvalidArgument, "days must not be negative")
+	}
+	caller, err := s.manager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := s.getLink(ctx, req.ShortCode)
+	if err == redis.Nil {
+		return nil, status.Error(codes.NotFound, "URL not found")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "Redis error: %v", err)
+	}
+	if !caller.canManage(link) {
+		return nil, status.Error(codes.NotFound, "URL not found")
+	}
+
+	days := int(req.Days)
+	if days == 0 {
+		days = 30
+	}
+	if retention := s.plans.Plans[link.Plan].AnalyticsRetentionDays; days > retention {
+		days = retention
+	}
+
+	now := time.Now()
+	dates := make([]string, days)
+	cmds := make([]*redis.MapStringStringCmd, days)
+	_, err = s.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i := range cmds {
+			dates[i] = statsDate(now.AddDate(0, 0, -i))
+			cmds[i] = pipe.HGetAll(ctx, statsKey(req.ShortCode, dates[i]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Redis error: %v", err)
+	}
+
+	stats := &pb.LinkStats{ShortCode: req.ShortCode, Counters: map[string]int64{}}
+	for i, cmd := range cmds {
+		fields := cmd.Val()
+		if len(fields) == 0 {
+			continue
+		}
+		day := &pb.DailyStats{Date: dates[i], Counters: map[string]int64{}}
+		for field, raw := range fields {
+			n, _ := strconv.ParseInt(raw, 10, 64)
+			if field == clicksField {
+				day.Clicks = n
+				stats.Clicks += n
+				continue
+			}
+			day.Counters[field] = n
+			stats.Counters[field] += n
+		}
+		stats.Days = append(stats.Days, day)
+	}
+	return stats, nil
+}
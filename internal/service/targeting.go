@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"tinyurl/internal/geoip"
+	"tinyurl/internal/useragent"
+	pb "tinyurl/proto/tinyurl/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MaxTargetRules is the number of targeting rules a link can have.
+const MaxTargetRules = 20
+
+var (
+	countryRe  = regexp.MustCompile(`^[A-Z]{2}$`)
+	languageRe = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{1,8})*$`)
+)
+
+// TargetRule sends visitors matching every non-empty condition to URL.
+type TargetRule struct {
+	OS       []string `json:"os,omitempty"`
+	Device   []string `json:"device,omitempty"`
+	Browser  []string `json:"browser,omitempty"`
+	Country  []string `json:"country,omitempty"`
+	Language []string `json:"language,omitempty"`
+	URL      string   `json:"url"`
+}
+
+// Visitor is what targeting rules are matched against.
+type Visitor struct {
+	Agent    useragent.Agent
+	Country  string // ISO 3166-1 code, "" if unknown
+	Language string // Preferred language tag in lower case, "" if unknown
+}
+
+func (r *TargetRule) match(v Visitor) bool {
+	return matchCondition(r.OS, v.Agent.OS) &&
+		matchCondition(r.Device, v.Agent.Device) &&
+		matchCondition(r.Browser, v.Agent.Browser) &&
+		matchCountry(r.Country, v.Country) &&
+		matchLanguage(r.Language, v.Language)
+}
+
+func matchCondition(accepted []string, value string) bool {
+	return len(accepted) == 0 || slices.Contains(accepted, value)
+}
+
+func matchCountry(accepted []string, country string) bool {
+	if len(accepted) == 0 {
+		return true
+	}
+	for _, c := range accepted {
+		if c == country || slices.Contains(geoip.Groups[c], country) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchLanguage accepts a tag and its more specific variants, so "pt"
+// matches "pt-br" but "pt-br" doesn't match "pt".
+func matchLanguage(accepted []string, language string) bool {
+	if len(accepted) == 0 {
+		return true
+	}
+	for _, l := range accepted {
+		if language == l || strings.HasPrefix(language, l+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// preferredLanguage returns the tag with the highest quality in an
+// Accept-Language header, the first one on ties.
+func preferredLanguage(header string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q > bestQ {
+			best, bestQ = tag, q
+		}
+	}
+	return best
+}
+
+// visitor describes who is following a link. The country is only looked up
+// when one of the link's rules needs it.
+func (s *TinyURLService) visitor(ctx context.Context, link *Link, req *pb.GetOriginalRequest) Visitor {
+	v := Visitor{
+		Agent:    useragent.Parse(req.UserAgent),
+		Language: preferredLanguage(req.AcceptLanguage),
+	}
+	if s.GeoIP != nil && s.ClientIPs != nil && slices.ContainsFunc(link.Rules, func(r TargetRule) bool { return len(r.Country) > 0 }) {
+		v.Country = s.GeoIP.Country(s.ClientIPs.FromContext(ctx))
+	}
+	return v
+}
+
+// target returns the destination of the first rule the visitor matches
+// and its 1-based index, or 0 if none does.
+func (l *Link) target(v Visitor) (string, int) {
+	for i := range l.Rules {
+		if l.Rules[i].match(v) {
+			return l.Rules[i].URL, i + 1
+		}
+	}
+	return "", 0
+}
+
+// checkRules validates targeting rules and their destinations.
+func (s *TinyURLService) checkRules(params []string, rules []*pb.TargetRule) ([]TargetRule, error) {
+	if len(rules) > MaxTargetRules {
+		return nil, status.Errorf(codes.InvalidArgument, "At most %d rules are allowed", MaxTargetRules)
+	}
+
+	var out []TargetRule
+	for i, r := range rules {
+		rule := TargetRule{}
+		var err error
+		if rule.OS, err = checkCondition(i, "os", r.Os, useragent.OSes); err != nil {
+			return nil, err
+		}
+		if rule.Device, err = checkCondition(i, "device", r.Device, useragent.Devices); err != nil {
+			return nil, err
+		}
+		if rule.Browser, err = checkCondition(i, "browser", r.Browser, useragent.Browsers); err != nil {
+			return nil, err
+		}
+		if rule.Country, err = checkCountries(i, r.Country); err != nil {
+			return nil, err
+		}
+		if rule.Language, err = checkLanguages(i, r.Language); err != nil {
+			return nil, err
+		}
+		if len(rule.OS)+len(rule.Device)+len(rule.Browser)+len(rule.Country)+len(rule.Language) == 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "rules[%d]: at least one condition is required", i)
+		}
+
+		if r.Url == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "rules[%d]: url is required", i)
+		}
+		if rule.URL, err = s.checkDestination(r.Url); err != nil {
+			return nil, status.Errorf(status.Code(err), "rules[%d]: %s", i, status.Convert(err).Message())
+		}
+		if rule.URL, err = templateDestination(params, rule.URL); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "rules[%d]: %v", i, err)
+		}
+		out = append(out, rule)
+	}
+	return out, nil
+}
+
+func checkCondition(i int, field string, values, known []string) ([]string, error) {
+	var out []string
+	for _, v := range values {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if !slices.Contains(known, v) {
+			return nil, status.Errorf(codes.InvalidArgument, "rules[%d]: unknown %s %q, expected one of %s", i, field, v, strings.Join(known, ", "))
+		}
+		if !slices.Contains(out, v) {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+func checkCountries(i int, values []string) ([]string, error) {
+	var out []string
+	for _, v := range values {
+		v = strings.ToUpper(strings.TrimSpace(v))
+		if _, group := geoip.Groups[v]; !group && !countryRe.MatchString(v) {
+			return nil, status.Errorf(codes.InvalidArgument, "rules[%d]: invalid country %q, expected an ISO 3166-1 code or a group like EU", i, v)
+		}
+		if !slices.Contains(out, v) {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+func checkLanguages(i int, values []string) ([]string, error) {
+	var out []string
+	for _, v := range values {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if !languageRe.MatchString(v) {
+			return nil, status.Errorf(codes.InvalidArgument, "rules[%d]: invalid language %q, expected a tag like \"id\" or \"pt-BR\"", i, v)
+		}
+		if !slices.Contains(out, v) {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+func rulesToProto(rules []TargetRule) []*pb.TargetRule {
+	var out []*pb.TargetRule
+	for _, r := range rules {
+		out = append(out, &pb.TargetRule{
+			Os:       r.OS,
+			Device:   r.Device,
+			Browser:  r.Browser,
+			Country:  r.Country,
+			Language: r.Language,
+			Url:      r.URL,
+		})
+	}
+	return out
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MaxTemplateParams is the number of placeholders a template link can have.
+const MaxTemplateParams = 10
+
+// Template links have placeholders like {1} or {id} in their destination,
+// filled from the path segments after the short code. Links are only
+// templates when created as one, braces in other links are just braces.
+var (
+	placeholderRe        = regexp.MustCompile(`\{(\w+)\}`)
+	escapedPlaceholderRe = regexp.MustCompile(`%7B(\w+)%7D`)
+)
+
+// templateParams lists the placeholders of a template in the order they
+// are taken from the path: {1}, {2}, ... first, then named ones in the
+// order they appear.
+func templateParams(tmpl string) []string {
+	var numbered, named []string
+	seen := map[string]bool{}
+	for _, m := range placeholderRe.FindAllStringSubmatch(tmpl, -1) {
+		name := m[1]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if _, err := strconv.Atoi(name); err == nil {
+			numbered = append(numbered, name)
+		} else {
+			named = append(named, name)
+		}
+	}
+	slices.SortFunc(numbered, func(a, b string) int {
+		x, _ := strconv.Atoi(a)
+		y, _ := strconv.Atoi(b)
+		return x - y
+	})
+	return append(numbered, named...)
+}
+
+// templateURL turns the placeholders of a normalized destination back
+// from their escaped form into {name}.
+func templateURL(u string) string {
+	return escapedPlaceholderRe.ReplaceAllString(u, "{$1}")
+}
+
+// params are the placeholders of a template link, none for other links.
+func (l *Link) params() []string {
+	if !l.Template {
+		return nil
+	}
+	return templateParams(l.LongURL)
+}
+
+// checkTemplate validates the placeholders of a template destination and
+// the patterns their values have to match.
+func checkTemplate(longURL string, template bool, patterns map[string]string) error {
+	if !template {
+		if len(patterns) > 0 {
+			return status.Error(codes.InvalidArgument, "param_patterns are only allowed on template links")
+		}
+		return nil
+	}
+	params := templateParams(longURL)
+	if len(params) == 0 {
+		return status.Error(codes.InvalidArgument, "Template links need at least one placeholder like {1} or {id} in long_url")
+	}
+	if len(params) > MaxTemplateParams {
+		return status.Errorf(codes.InvalidArgument, "long_url can have at most %d placeholders", MaxTemplateParams)
+	}
+	for i, name := range params {
+		if n, err := strconv.Atoi(name); err == nil && n != i+1 {
+			return status.Errorf(codes.InvalidArgument, "Numbered placeholders must be {1}, {2}, ... without gaps, found {%s}", name)
+		}
+	}
+
+	for name, pattern := range patterns {
+		if !slices.Contains(params, name) {
+			return status.Errorf(codes.InvalidArgument, "param_patterns: long_url has no placeholder {%s}", name)
+		}
+		if _, err := regexp.Compile(anchored(pattern)); err != nil {
+			return status.Errorf(codes.InvalidArgument, "param_patterns: invalid pattern for {%s}: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func anchored(pattern string) string {
+	return "^(?:" + pattern + ")$"
+}
+
+// templateArgs takes the values of the link's placeholders from the start
+// of the path and returns them with the rest of the path.
+func (l *Link) templateArgs(path string) (map[string]string, string, error) {
+	params := l.params()
+	if len(params) == 0 {
+		return nil, path, nil
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if segments[0] == "" {
+		segments = nil
+	}
+	if len(segments) < len(params) {
+		return nil, "", status.Errorf(codes.InvalidArgument, "Missing value for {%s}", params[len(segments)])
+	}
+
+	args := map[string]string{}
+	for i, name := range params {
+		value := segments[i]
+		// Escaping keeps them, and they'd walk up the destination path
+		if value == "." || value == ".." {
+			return nil, "", status.Errorf(codes.InvalidArgument, "Invalid value for {%s}", name)
+		}
+		if pattern, ok := l.ParamPatterns[name]; ok {
+			re, err := regexp.Compile(anchored(pattern))
+			if err != nil || !re.MatchString(value) {
+				return nil, "", status.Errorf(codes.InvalidArgument, "Invalid value for {%s}", name)
+			}
+		}
+		args[name] = value
+	}
+
+	rest := ""
+	if len(segments) > len(params) {
+		rest = "/" + strings.Join(segments[len(params):], "/")
+		if strings.HasSuffix(path, "/") {
+			rest += "/"
+		}
+	}
+	return args, rest, nil
+}
+
+// fillTemplate replaces the placeholders of a destination with escaped
+// values, path escaped before the query and query escaped after it.
+// Placeholders without a value are left alone.
+func fillTemplate(tmpl string, args map[string]string) string {
+	if len(args) == 0 {
+		return tmpl
+	}
+
+	queryStart := strings.IndexByte(tmpl, '?')
+	var b strings.Builder
+	last := 0
+	for _, m := range placeholderRe.FindAllStringSubmatchIndex(tmpl, -1) {
+		value, ok := args[tmpl[m[2]:m[3]]]
+		if !ok {
+			continue
+		}
+		b.WriteString(tmpl[last:m[0]])
+		if queryStart >= 0 && m[0] > queryStart {
+			b.WriteString(url.QueryEscape(value))
+		} else {
+			b.WriteString(url.PathEscape(value))
+		}
+		last = m[1]
+	}
+	b.WriteString(tmpl[last:])
+	return b.String()
+}
+
+func describeTemplate(params []string) string {
+	if len(params) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("{%s}", strings.Join(params, "}/{"))
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"tinyurl/internal/threat"
+	pb "tinyurl/proto/tinyurl/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const threatWarning = "This link points to a site reported as dangerous. It may try to steal your information or install malware."
+
+// checkThreat rejects destinations that match the local threat list.
+func (s *TinyURLService) checkThreat(longURL string) error {
+	if _, ok := s.threats.Match(longURL); ok {
+		return status.Error(codes.PermissionDenied, "Destination is on the threat list")
+	}
+	return nil
+}
+
+// flagIfThreat marks an existing link whose destination started matching
+// the threat list since it was created. Flags are sticky.
+func (s *TinyURLService) flagIfThreat(ctx context.Context, code string, link *Link) {
+	if link.Flagged != "" {
+		return
+	}
+	expr, ok := s.threats.Match(link.LongURL)
+	if !ok {
+		return
+	}
+
+	link.Flagged = fmt.Sprintf("threat list match (%s)", expr)
+	if err := s.updateLink(ctx, code, link); err != nil {
+		fmt.Printf("Failed to flag link %s: %v\n", code, err)
+	}
+}
+
+func (s *TinyURLService) ImportThreatList(ctx context.Context, req *pb.ImportThreatListRequest) (*pb.ImportThreatListResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	total, err := s.threats.Import(ctx, req.Prefixes, req.Replace)
+	if errors.Is(err, threat.ErrInvalidPrefix) {
+		return nil, status.Errorf(codes.InvalidArgument, "Failed to import threat list: %v", err)
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "Redis error: %v", err)
+	}
+	return &pb.ImportThreatListResponse{Total: int32(total)}, nil
+}
@@ -2,10 +2,18 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
+	"regexp"
+	"strconv"
 	"time"
 
+	"tinyurl/internal/clientip"
+	"tinyurl/internal/geoip"
+	"tinyurl/internal/policy"
+	"tinyurl/internal/threat"
+	"tinyurl/internal/useragent"
 	pb "tinyurl/proto/tinyurl/v1"
 
 	"github.com/redis/go-redis/v9"
@@ -13,18 +21,61 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// Short codes never contain ':' so they can't collide with the prefixed
+// keys used for everything else in Redis.
+var validShortCode = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// reservedCodes are paths served by the HTTP server itself.
+var reservedCodes = map[string]bool{
+	"tinyurl": true,
+	"v1":      true,
+	"report":  true,
+	"preview": true,
+}
+
 type TinyURLService struct {
 	pb.UnimplementedTinyURLServer
-	rdb              *redis.Client
-	serverURL        string
-	exclusiveLinkExp int
+	rdb       *redis.Client
+	serverURL string
+	plans     *Plans
+	pow       *ProofOfWork // nil when proof-of-work is disabled
+	policies  *policy.Engine
+	threats   *threat.List
+	misses    *missCache
+
+	// TombstoneTTL is how long expired links answer with 410 Gone and keep
+	// their alias reserved. 0 disables tombstones.
+	TombstoneTTL time.Duration
+
+	// GeoIP and ClientIPs locate visitors for country rules. Countries are
+	// unknown while either is nil.
+	GeoIP     *geoip.DB
+	ClientIPs *clientip.Resolver
+
+	// TimeZone is where schedules of links without a time zone of their own
+	// are read.
+	TimeZone *time.Location
+
+	// UnlockSecret signs the tokens of unlocked password protected links,
+	// which are valid for UnlockTTL.
+	UnlockSecret []byte
+	UnlockTTL    time.Duration
 }
 
-func NewTinyURLService(rdb *redis.Client, serverURL string, exclusiveLinkExp int) *TinyURLService {
+func NewTinyURLService(rdb *redis.Client, serverURL string, plans *Plans, pow *ProofOfWork, policies *policy.Engine, threats *threat.List) *TinyURLService {
 	return &TinyURLService{
-		rdb:              rdb,
-		serverURL:        serverURL,
-		exclusiveLinkExp: exclusiveLinkExp,
+		rdb:       rdb,
+		serverURL: serverURL,
+		plans:     plans,
+		pow:       pow,
+		policies:  policies,
+		threats:   threats,
+		misses:    newMissCache(15*time.Second, 100000),
+
+		TombstoneTTL: DefaultTombstoneTTL,
+		TimeZone:     time.UTC,
+		UnlockSecret: randomSecret(),
+		UnlockTTL:    time.Hour,
 	}
 }
 
@@ -34,14 +85,106 @@ func (s *TinyURLService) Shorten(ctx context.Context, req *pb.ShortenRequest) (*
 		return nil, status.Error(codes.InvalidArgument, "long_url is required")
 	}
 
+	longURL, err := s.checkDestination(req.LongUrl)
+	if err != nil {
+		return nil, err
+	}
+	fallbacks, err := s.checkFallbacks(longURL, req.FallbackUrls, nil)
+	if err != nil {
+		return nil, err
+	}
+	lastResort, err := s.checkLastResort(req.LastResortUrl)
+	if err != nil {
+		return nil, err
+	}
+	redirectType, err := redirectTypeFromProto(req.RedirectType)
+	if err != nil {
+		return nil, err
+	}
+	queryPassthrough, err := queryPassthroughFromProto(req.QueryPassthrough)
+	if err != nil {
+		return nil, err
+	}
+	var params []string
+	if req.Template {
+		longURL = templateURL(longURL)
+		params = templateParams(longURL)
+	}
+	if err := checkTemplate(longURL, req.Template, req.ParamPatterns); err != nil {
+		return nil, err
+	}
+	rules, err := s.checkRules(params, req.Rules)
+	if err != nil {
+		return nil, err
+	}
+	variants, err := s.checkVariants(params, req.Variants)
+	if err != nil {
+		return nil, err
+	}
+	notBefore, err := checkNotBefore(req.NotBefore)
+	if err != nil {
+		return nil, err
+	}
+	timeZone, err := checkTimeZone(req.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+	schedules, err := s.checkSchedules(params, req.Schedules)
+	if err != nil {
+		return nil, err
+	}
+	maxClicks, err := checkMaxClicks(req.MaxClicks)
+	if err != nil {
+		return nil, err
+	}
+	access, err := checkAccessPolicy(req.Access)
+	if err != nil {
+		return nil, err
+	}
+	var passwordHash string
+	if req.Password != "" {
+		if passwordHash, err = hashPassword(req.Password); err != nil {
+			return nil, err
+		}
+	}
+
+	caller, err := s.plans.CallerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	plan := caller.Plan
+
+	expHours := int(req.ExpiresInHours)
+	if expHours < 0 {
+		return nil, status.Error(codes.InvalidArgument, "expires_in_hours must not be negative")
+	}
+	if expHours == 0 {
+		expHours = plan.MaxExpiryHours
+	}
+	if plan.MaxExpiryHours > 0 && expHours > plan.MaxExpiryHours {
+		return nil, status.Errorf(codes.InvalidArgument, "expires_in_hours exceeds your plan limit. %s", plan.Describe())
+	}
+
 	var shortCode, shortURL string
 
 	if req.ShortCode != "" {
+		if !plan.CustomAlias {
+			return nil, status.Errorf(codes.PermissionDenied, "Custom alias is not available on your plan. %s", plan.Describe())
+		}
 		shortCode = req.ShortCode
+		if !validShortCode.MatchString(shortCode) {
+			return nil, status.Error(codes.InvalidArgument, "Short code may only contain letters, digits, '-' and '_', up to 64 characters")
+		}
+		if reservedCodes[shortCode] {
+			return nil, status.Error(codes.InvalidArgument, "Short code is reserved. Try another one!")
+		}
 		// check collision
 		if _, err := s.rdb.Get(ctx, shortCode).Result(); err == nil {
 			return nil, status.Error(codes.AlreadyExists, "Short code already exists. Try another one!")
 		}
+		if ttl, err := s.rdb.TTL(ctx, tombstonePrefix+shortCode).Result(); err == nil && ttl > 0 {
+			return nil, status.Errorf(codes.AlreadyExists, "Short code expired recently and can't be reused before %s. Try another one!", time.Now().Add(ttl).UTC().Format(time.DateOnly))
+		}
 	} else {
 		// Generate
 		charset := "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
@@ -54,37 +197,260 @@ func (s *TinyURLService) Shorten(ctx context.Context, req *pb.ShortenRequest) (*
 
 	shortURL = fmt.Sprintf("%s/%s", s.serverURL, shortCode)
 
+	// Authenticated callers are exempt from proof-of-work. The challenge is
+	// checked last so it isn't used up by a request that fails anyway.
+	powRequired := s.pow != nil && !caller.Authenticated()
+	if powRequired {
+		if err := s.pow.Verify(ctx, req.PowChallenge, req.PowNonce); err != nil {
+			return nil, err
+		}
+	}
+
 	// Save to Redis
-	exp := time.Duration(s.exclusiveLinkExp) * time.Hour
-	err := s.rdb.Set(ctx, shortCode, req.LongUrl, exp).Err()
-	if err != nil {
+	exp := time.Duration(expHours) * time.Hour
+	link := &Link{
+		LongURL:    longURL,
+		CreatedAt:  time.Now(),
+		Owner:      caller.Owner(),
+		Plan:       plan.Name,
+		Fallbacks:  fallbacks,
+		LastResort: lastResort,
+
+		RedirectType:     redirectType,
+		PathPassthrough:  req.PathPassthrough,
+		QueryPassthrough: queryPassthrough,
+		Template:         req.Template,
+		ParamPatterns:    req.ParamPatterns,
+		Rules:            rules,
+		Variants:         variants,
+		NotBefore:        notBefore,
+		TimeZone:         timeZone,
+		Schedules:        schedules,
+		PasswordHash:     passwordHash,
+		MaxClicks:        maxClicks,
+		Access:           access,
+		Interstitial:     req.Interstitial,
+	}
+	err = s.createLink(ctx, shortCode, link, exp)
+	if err != nil && powRequired {
+		s.pow.Release(ctx, req.PowChallenge)
+	}
+	if errors.Is(err, errCodeTaken) {
+		// Someone else took the code since the check above
+		return nil, status.Error(codes.AlreadyExists, "Short code already exists. Try another one!")
+	} else if err != nil {
 		return nil, status.Errorf(codes.Internal, "Failed to save to Redis: %v", err)
 	}
+	s.misses.Remove(shortCode)
+
+	if powRequired {
+		s.pow.Record(ctx)
+	}
 
 	elapsed := time.Since(start)
 	fmt.Printf("[DEBUG] Shorten processed in %s\n", elapsed)
 
+	message := "Exclusive link will never expire"
+	if expHours > 0 {
+		message = fmt.Sprintf("Exclusive link will be expired in %d hours", expHours)
+	}
+	if params := describeTemplate(params); params != "" {
+		message = fmt.Sprintf("%s. Open it as %s/%s", message, shortURL, params)
+	}
+
 	return &pb.ShortenResponse{
 		ShortUrl:    shortURL,
-		LongUrl:     req.LongUrl,
-		Message:     fmt.Sprintf("Exclusive link will be expired in %d hours", s.exclusiveLinkExp),
+		LongUrl:     longURL,
+		Message:     fmt.Sprintf("%s. %s", message, plan.Describe()),
 		ElapsedTime: elapsed.String(),
+		Plan:        plan.Name,
 	}, nil
 }
 
+func disabledError(link *Link) error {
+	return errorWithInfo(codes.FailedPrecondition, "This link has been disabled", ReasonLinkDisabled, map[string]string{
+		"reason": link.DisabledReason,
+		"legal":  strconv.FormatBool(link.DisabledLegal),
+	})
+}
+
+// checkDestination normalizes a destination URL and runs it through every
+// check a new destination has to pass.
+func (s *TinyURLService) checkDestination(rawURL string) (string, error) {
+	longURL, err := NormalizeURL(rawURL)
+	if err != nil {
+		return "", err
+	}
+	// A link to ourselves would redirect in a loop
+	if hostOf(longURL) == hostOf(s.serverURL) {
+		return "", status.Error(codes.InvalidArgument, "long_url must not point back to this service")
+	}
+	if err := s.checkDomain(longURL); err != nil {
+		return "", err
+	}
+	if err := s.checkThreat(longURL); err != nil {
+		return "", err
+	}
+	return longURL, nil
+}
+
 func (s *TinyURLService) GetOriginal(ctx context.Context, req *pb.GetOriginalRequest) (*pb.GetOriginalResponse, error) {
 	if req.ShortCode == "" {
 		return nil, status.Error(codes.InvalidArgument, "short_code is required")
 	}
 
-	longURL, err := s.rdb.Get(ctx, req.ShortCode).Result()
+	if s.misses.Has(req.ShortCode) {
+		return nil, status.Error(codes.NotFound, "URL not found")
+	}
+
+	link, err := s.getLink(ctx, req.ShortCode)
 	if err == redis.Nil {
+		if t, err := s.getTombstone(ctx, req.ShortCode); err != nil {
+			return nil, status.Errorf(codes.Unavailable, "Redis error: %v", err)
+		} else if t != nil {
+			return nil, expiredError(t)
+		}
+		s.misses.Add(req.ShortCode)
 		return nil, status.Error(codes.NotFound, "URL not found")
 	} else if err != nil {
-		return nil, status.Errorf(codes.Internal, "Redis error: %v", err)
+		// Usually Redis being down, which clients should retry
+		return nil, status.Errorf(codes.Unavailable, "Redis error: %v", err)
 	}
 
-	return &pb.GetOriginalResponse{
-		LongUrl: longURL,
-	}, nil
+	// Template links take their values from the leading path segments
+	args, extraPath, err := link.templateArgs(req.Path)
+	if err != nil {
+		return nil, err
+	}
+	// Extra path segments only resolve on links that pass them through
+	if extraPath != "" && extraPath != "/" && !link.PathPassthrough {
+		return nil, status.Error(codes.NotFound, "URL not found")
+	}
+
+	if link.Disabled {
+		return nil, disabledError(link)
+	}
+
+	// Outsiders don't get to learn anything else about the link
+	unlocked := link.PasswordHash != "" && s.unlocked(req.ShortCode, link, req.UnlockToken)
+	if err := s.checkAccess(ctx, link, unlocked); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if now.Before(link.NotBefore) {
+		return nil, notActiveError(link.NotBefore.In(link.location(s.TimeZone)))
+	}
+
+	if link.PasswordHash != "" && !unlocked {
+		return nil, passwordRequiredError()
+	}
+
+	// The policy may have changed since the link was created
+	if err := s.checkDomain(link.LongURL); err != nil {
+		return nil, err
+	}
+
+	s.flagIfThreat(ctx, req.ShortCode, link)
+
+	// Targeting rules come first, then schedules and the A/B split, and
+	// visitors are sent elsewhere while the destination is down
+	visitor := s.visitor(ctx, link, req)
+	dest, rule := link.target(visitor)
+	var schedule int
+	if rule == 0 {
+		dest, schedule = link.schedule(now, s.TimeZone)
+	}
+	var variant *Variant
+	if rule == 0 && schedule == 0 {
+		variant = link.variant(req.ShortCode, req.Variant, s.visitorID(ctx, req.UserAgent))
+	}
+	fallback := false
+	if variant != nil {
+		dest = variant.URL
+	} else if rule == 0 && schedule == 0 {
+		dest, fallback = link.Destination()
+	}
+	if dest != link.LongURL {
+		if err := s.checkDomain(dest); err != nil {
+			return nil, err
+		}
+	}
+
+	dest, err = link.passthrough(fillTemplate(dest, args), extraPath, req.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.GetOriginalResponse{
+		LongUrl:      dest,
+		Fallback:     fallback,
+		Rule:         int32(rule),
+		Targeted:     len(link.Rules) > 0 || len(link.Variants) > 0,
+		Protected:    link.PasswordHash != "",
+		Limited:      link.MaxClicks > 0,
+		Interstitial: link.Interstitial,
+		RedirectType: redirectTypeToProto(link.RedirectType),
+	}
+	if variant != nil {
+		resp.Variant = variant.Name
+	}
+	// Browsers cache permanent redirects, which mustn't outlive the link
+	if permanentRedirect(link.RedirectType) {
+		if ttl, err := s.rdb.TTL(ctx, req.ShortCode).Result(); err == nil && ttl > 0 {
+			resp.ExpiresAt = now.Add(ttl).Unix()
+		}
+		// Schedule windows start and end on whole minutes, so the redirect
+		// holds until the next one
+		if len(link.Schedules) > 0 {
+			nextMinute := now.Truncate(time.Minute).Add(time.Minute).Unix()
+			if resp.ExpiresAt == 0 || nextMinute < resp.ExpiresAt {
+				resp.ExpiresAt = nextMinute
+			}
+		}
+	}
+	if link.Flagged != "" {
+		resp.Flagged = true
+		resp.Warning = threatWarning
+	} else if _, ok := s.threats.Match(dest); ok && dest != link.LongURL {
+		// Filled in or passed through parts can make a match of their own
+		resp.Flagged = true
+		resp.Warning = threatWarning
+	}
+
+	// Crawlers and link previews aren't visitors, and don't get to read
+	// where a link with a click limit goes without using a click either
+	if visitor.Agent.Device == useragent.DeviceBot || req.Preview {
+		if link.MaxClicks > 0 {
+			resp.LongUrl = ""
+			resp.Flagged, resp.Warning = false, ""
+		}
+	} else {
+		if link.MaxClicks > 0 {
+			ok, err := s.useClick(ctx, req.ShortCode, link)
+			if err != nil {
+				return nil, status.Errorf(codes.Unavailable, "Redis error: %v", err)
+			}
+			if !ok {
+				// Someone else took the last click
+				if t, err := s.getTombstone(ctx, req.ShortCode); err == nil && t != nil {
+					return nil, expiredError(t)
+				}
+				return nil, status.Error(codes.NotFound, "URL not found")
+			}
+		}
+
+		var counters []string
+		if rule > 0 {
+			counters = append(counters, ruleCounter(rule))
+		}
+		if schedule > 0 {
+			counters = append(counters, scheduleCounter(schedule))
+		}
+		if variant != nil {
+			counters = append(counters, variantCounter(variant.Name))
+		}
+		s.recordClick(ctx, req.ShortCode, link, counters...)
+	}
+	return resp, nil
 }
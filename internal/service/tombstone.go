@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/codes"
+)
+
+// tombstonePrefix keys remember expired links so visitors get 410 Gone
+// instead of 404 and the alias isn't handed out again right away.
+const tombstonePrefix = "tombstone:"
+
+// DefaultTombstoneTTL is how long a tombstone outlives its link.
+const DefaultTombstoneTTL = 30 * 24 * time.Hour
+
+type tombstone struct {
+	Code      string    `json:"code"`
+	ExpiredAt time.Time `json:"expired_at"`
+	Owner     string    `json:"owner,omitempty"`
+	Reason    string    `json:"reason,omitempty"` // Empty for links that reached their expiry
+}
+
+// getTombstone returns the tombstone of an expired link, or nil if there is
+// none. Tombstones are written when the link is created, so one for a link
+// that hasn't reached its expiry yet is ignored. Deleting a link replaces it
+// with one that expired right away.
+func (s *TinyURLService) getTombstone(ctx context.Context, code string) (*tombstone, error) {
+	data, err := s.rdb.Get(ctx, tombstonePrefix+code).Result()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var t tombstone
+	if err := json.Unmarshal([]byte(data), &t); err != nil {
+		return nil, err
+	}
+	if time.Now().Before(t.ExpiredAt) {
+		return nil, nil
+	}
+	return &t, nil
+}
+
+// buryLink deletes a link in pipe and leaves a tombstone in its place, so
+// the code answers 410 Gone and isn't handed out again for TombstoneTTL.
+// The returned command reports whether the link still existed.
+func (s *TinyURLService) buryLink(ctx context.Context, pipe redis.Pipeliner, code string, link *Link) (*redis.IntCmd, error) {
+	del := pipe.Del(ctx, code, clicksLeftPrefix+code)
+	pipe.SRem(ctx, brokenLinksKey, code)
+	if s.TombstoneTTL <= 0 {
+		pipe.Del(ctx, tombstonePrefix+code)
+		return del, nil
+	}
+
+	stone, err := json.Marshal(tombstone{
+		Code:      code,
+		ExpiredAt: time.Now(),
+		Owner:     link.Owner,
+		Reason:    ExpiredReasonDeleted,
+	})
+	if err != nil {
+		return nil, err
+	}
+	pipe.Set(ctx, tombstonePrefix+code, stone, s.TombstoneTTL)
+	return del, nil
+}
+
+func expiredError(t *tombstone) error {
+	msg := "This link has expired"
+	switch t.Reason {
+	case ExpiredReasonMaxClicks:
+		msg = "This link reached its click limit"
+	case ExpiredReasonDeleted:
+		msg = "This link was removed"
+	}
+	return errorWithInfo(codes.NotFound, msg, ReasonLinkExpired, map[string]string{
+		"expired_at": t.ExpiredAt.UTC().Format(time.RFC3339),
+		"reason":     t.Reason,
+	})
+}
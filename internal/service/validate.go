@@ -0,0 +1,82 @@
+package service
+
+import (
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/idna"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MaxURLLength is the longest destination URL accepted by Shorten.
+const MaxURLLength = 2048
+
+var allowedSchemes = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// NormalizeURL validates a destination URL and returns its canonical form:
+// lowercase scheme and host, punycode host and no default port. Only http
+// and https URLs with a host are accepted, so things like javascript: or
+// data: URIs never reach the redirect handler.
+func NormalizeURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if len(raw) > MaxURLLength {
+		return "", status.Errorf(codes.InvalidArgument, "long_url must not be longer than %d characters", MaxURLLength)
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", status.Error(codes.InvalidArgument, "long_url is not a valid URL")
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	defaultPort, ok := allowedSchemes[u.Scheme]
+	if !ok {
+		return "", status.Error(codes.InvalidArgument, "long_url must start with http:// or https://")
+	}
+	if u.Opaque != "" || u.Hostname() == "" {
+		return "", status.Error(codes.InvalidArgument, "long_url must contain a host")
+	}
+	if u.User != nil {
+		return "", status.Error(codes.InvalidArgument, "long_url must not contain credentials")
+	}
+
+	host, err := normalizeHost(u.Hostname())
+	if err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "long_url has an invalid host: %v", err)
+	}
+	if port := u.Port(); port != "" && port != defaultPort {
+		host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	u.Host = host
+	return u.String(), nil
+}
+
+// normalizeHost lowercases a host name and converts internationalized names
+// to punycode. IP addresses are returned in their canonical form.
+func normalizeHost(host string) (string, error) {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return addr.Unmap().String(), nil
+	}
+	return idna.Lookup.ToASCII(strings.TrimSuffix(host, "."))
+}
+
+// hostOf returns the normalized host name of a URL without its port.
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	host, err := normalizeHost(u.Hostname())
+	if err != nil {
+		return ""
+	}
+	return host
+}
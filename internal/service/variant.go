@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"slices"
+
+	pb "tinyurl/proto/tinyurl/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MaxVariants is the number of variants an A/B split can have.
+const MaxVariants = 10
+
+var validVariantName = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// Variant is one destination of an A/B split.
+type Variant struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Weight int    `json:"weight"`
+}
+
+// variant returns the variant a visitor gets. A variant they were given
+// before is kept while it still exists, even if it's paused. Otherwise the
+// choice is a hash of the link and visitor, so the same visitor lands on
+// the same variant without a cookie as long as the weights don't change.
+func (l *Link) variant(code, previous, visitorID string) *Variant {
+	if len(l.Variants) == 0 {
+		return nil
+	}
+	for i := range l.Variants {
+		if l.Variants[i].Name == previous {
+			return &l.Variants[i]
+		}
+	}
+
+	total := 0
+	for _, v := range l.Variants {
+		total += v.Weight
+	}
+	h := fnv.New64a()
+	h.Write([]byte(code + "\x00" + visitorID))
+	n := int(h.Sum64() % uint64(total))
+	for i := range l.Variants {
+		if n < l.Variants[i].Weight {
+			return &l.Variants[i]
+		}
+		n -= l.Variants[i].Weight
+	}
+	return nil
+}
+
+// visitorID identifies a visitor well enough to keep them on one variant.
+func (s *TinyURLService) visitorID(ctx context.Context, userAgent string) string {
+	var ip string
+	if s.ClientIPs != nil {
+		ip = s.ClientIPs.FromContext(ctx)
+	}
+	return ip + "\x00" + userAgent
+}
+
+func (s *TinyURLService) checkVariants(params []string, variants []*pb.Variant) ([]Variant, error) {
+	if len(variants) == 0 {
+		return nil, nil
+	}
+	if len(variants) < 2 {
+		return nil, status.Error(codes.InvalidArgument, "variants: at least 2 variants are required")
+	}
+	if len(variants) > MaxVariants {
+		return nil, status.Errorf(codes.InvalidArgument, "At most %d variants are allowed", MaxVariants)
+	}
+
+	var out []Variant
+	total := 0
+	for i, v := range variants {
+		name := v.Name
+		if name == "" {
+			name = string(rune('a' + i))
+		}
+		if !validVariantName.MatchString(name) {
+			return nil, status.Errorf(codes.InvalidArgument, "variants[%d]: name may only contain letters, digits, '-' and '_', up to 32 characters", i)
+		}
+		if slices.ContainsFunc(out, func(o Variant) bool { return o.Name == name }) {
+			return nil, status.Errorf(codes.InvalidArgument, "variants[%d]: name %q is used twice", i, name)
+		}
+		if v.Weight < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "variants[%d]: weight must not be negative", i)
+		}
+		total += int(v.Weight)
+
+		if v.Url == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "variants[%d]: url is required", i)
+		}
+		u, err := s.checkDestination(v.Url)
+		if err != nil {
+			return nil, status.Errorf(status.Code(err), "variants[%d]: %s", i, status.Convert(err).Message())
+		}
+		if u, err = templateDestination(params, u); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "variants[%d]: %v", i, err)
+		}
+		out = append(out, Variant{Name: name, URL: u, Weight: int(v.Weight)})
+	}
+	if total == 0 {
+		return nil, status.Error(codes.InvalidArgument, "variants: at least one variant needs a weight above 0")
+	}
+	return out, nil
+}
+
+func variantCounter(name string) string {
+	return "variant:" + name
+}
+
+func variantsToProto(variants []Variant) []*pb.Variant {
+	var out []*pb.Variant
+	for _, v := range variants {
+		out = append(out, &pb.Variant{Name: v.Name, Url: v.URL, Weight: int32(v.Weight)})
+	}
+	return out
+}
+
+// templateDestination prepares an alternative destination of a template
+// link, which may only use the placeholders of the link's own destination,
+// since those are the ones taken from the path. Destinations of other
+// links are returned as they are.
+func templateDestination(params []string, u string) (string, error) {
+	if len(params) == 0 {
+		return u, nil
+	}
+	u = templateURL(u)
+	for _, name := range templateParams(u) {
+		if !slices.Contains(params, name) {
+			return "", fmt.Errorf("long_url has no placeholder {%s}", name)
+		}
+	}
+	return u, nil
+}
@@ -0,0 +1,249 @@
+package threat
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// Canonicalize normalizes a URL the way Safe Browsing does before hashing:
+// no fragment, fully unescaped then re-escaped, lowercase host without
+// redundant dots, and a path without "." and ".." segments.
+// It returns the host and the path including the query.
+func Canonicalize(rawURL string) (host, path string, err error) {
+	rawURL = strings.NewReplacer("\t", "", "\r", "", "\n", "").Replace(strings.TrimSpace(rawURL))
+	if i := strings.IndexByte(rawURL, '#'); i >= 0 {
+		rawURL = rawURL[:i]
+	}
+	rawURL = unescapeRepeatedly(rawURL)
+
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "http://" + rawURL
+	}
+	scheme, rest, _ := strings.Cut(rawURL, "://")
+	if scheme == "" {
+		return "", "", fmt.Errorf("missing scheme")
+	}
+
+	hostPart, pathPart := rest, "/"
+	if i := strings.IndexAny(rest, "/?"); i >= 0 {
+		hostPart, pathPart = rest[:i], rest[i:]
+	}
+	if at := strings.LastIndexByte(hostPart, '@'); at >= 0 {
+		hostPart = hostPart[at+1:]
+	}
+	if i := strings.LastIndexByte(hostPart, ':'); i >= 0 && !strings.HasSuffix(hostPart, "]") {
+		hostPart = hostPart[:i]
+	}
+
+	host = canonicalHost(hostPart)
+	if host == "" {
+		return "", "", fmt.Errorf("missing host")
+	}
+
+	query := ""
+	if i := strings.IndexByte(pathPart, '?'); i >= 0 {
+		pathPart, query = pathPart[:i], pathPart[i:]
+	}
+	if !strings.HasPrefix(pathPart, "/") {
+		pathPart = "/" + pathPart
+	}
+
+	return escape(host), escape(canonicalPath(pathPart) + query), nil
+}
+
+func unescapeRepeatedly(s string) string {
+	for range 1024 {
+		u := unescape(s)
+		if u == s {
+			return s
+		}
+		s = u
+	}
+	return s
+}
+
+// unescape decodes every valid "%XX" escape and keeps anything else as is.
+// url.PathUnescape would reject the whole URL over a single stray '%',
+// letting it skip canonicalization.
+func unescape(s string) string {
+	if !strings.Contains(s, "%") {
+		return s
+	}
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]) {
+			n, _ := strconv.ParseUint(s[i+1:i+3], 16, 8)
+			b = append(b, byte(n))
+			i += 2
+			continue
+		}
+		b = append(b, s[i])
+	}
+	return string(b)
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func canonicalHost(host string) string {
+	host = asciiLower(strings.Trim(host, "."))
+	for strings.Contains(host, "..") {
+		host = strings.ReplaceAll(host, "..", ".")
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+
+	if ip, ok := parseIPv4(host); ok {
+		return ip
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return addr.String()
+	}
+	return host
+}
+
+// asciiLower lowercases without touching non-ASCII bytes, unlike
+// strings.ToLower which would replace invalid UTF-8.
+func asciiLower(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if 'A' <= c && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+	return string(b)
+}
+
+// parseIPv4 accepts the decimal, octal and hex forms browsers accept, such
+// as "0x7f.1" or "3279880203".
+func parseIPv4(host string) (string, bool) {
+	parts := strings.Split(host, ".")
+	if len(parts) > 4 {
+		return "", false
+	}
+	nums := make([]uint64, len(parts))
+	for i, p := range parts {
+		n, err := strconv.ParseUint(p, 0, 32)
+		if err != nil {
+			return "", false
+		}
+		nums[i] = n
+	}
+
+	var v uint64
+	for i, n := range nums[:len(nums)-1] {
+		if n > 255 {
+			return "", false
+		}
+		v |= n << (8 * (3 - i))
+	}
+	last := nums[len(nums)-1]
+	if last >= 1<<(8*(5-len(nums))) {
+		return "", false
+	}
+	v |= last
+
+	return fmt.Sprintf("%d.%d.%d.%d", v>>24, v>>16&0xff, v>>8&0xff, v&0xff), true
+}
+
+func canonicalPath(path string) string {
+	var out []string
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		switch seg {
+		case ".":
+			continue
+		case "..":
+			if len(out) > 0 {
+				out = out[:len(out)-1]
+			}
+			continue
+		case "":
+			// Collapse "//" but keep a trailing slash
+			if i != len(segments)-1 {
+				continue
+			}
+		}
+		out = append(out, seg)
+	}
+	result := "/" + strings.Join(out, "/")
+	if strings.HasSuffix(path, "/.") || strings.HasSuffix(path, "/..") {
+		result = strings.TrimSuffix(result, "/") + "/"
+	}
+	return result
+}
+
+// escape percent-escapes control characters, spaces, non-ASCII bytes, '#'
+// and '%'.
+func escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= 0x20 || c >= 0x7f || c == '#' || c == '%' {
+			fmt.Fprintf(&b, "%%%02X", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// Expressions returns the host suffix / path prefix combinations that are
+// looked up for a URL, e.g. for "a.b.example.com/1/2.html?x=1":
+// "a.b.example.com/1/2.html?x=1", "a.b.example.com/1/2.html",
+// "a.b.example.com/", "a.b.example.com/1/", "b.example.com/..." and so on.
+func Expressions(rawURL string) ([]string, error) {
+	host, path, err := Canonicalize(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var exprs []string
+	seen := map[string]bool{}
+	for _, h := range hostSuffixes(host) {
+		for _, p := range pathPrefixes(path) {
+			if e := h + p; !seen[e] {
+				seen[e] = true
+				exprs = append(exprs, e)
+			}
+		}
+	}
+	return exprs, nil
+}
+
+// hostSuffixes is the exact host plus up to four hosts formed from the last
+// five components, never the bare TLD. IP addresses are only used as is.
+func hostSuffixes(host string) []string {
+	hosts := []string{host}
+	if _, err := netip.ParseAddr(host); err == nil {
+		return hosts
+	}
+
+	parts := strings.Split(host, ".")
+	start := max(1, len(parts)-5)
+	for i := start; i < len(parts)-1 && len(hosts) < 5; i++ {
+		hosts = append(hosts, strings.Join(parts[i:], "."))
+	}
+	return hosts
+}
+
+// pathPrefixes is the exact path with and without query, plus up to four
+// prefixes starting from the root.
+func pathPrefixes(path string) []string {
+	paths := []string{path}
+	p, _, hasQuery := strings.Cut(path, "?")
+	if hasQuery {
+		paths = append(paths, p)
+	}
+
+	paths = append(paths, "/")
+	segments := strings.Split(strings.Trim(p, "/"), "/")
+	prefix := "/"
+	for i := 0; i < len(segments)-1 && i < 3; i++ {
+		prefix += segments[i] + "/"
+		paths = append(paths, prefix)
+	}
+	return paths
+}
@@ -0,0 +1,153 @@
+package threat
+
+import (
+	"slices"
+	"testing"
+)
+
+// The canonicalization examples of the Safe Browsing API documentation,
+// without the scheme, which isn't part of the hashed expressions.
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"http://host/%25%32%35", "host/%25"},
+		{"http://host/%25%32%35%25%32%35", "host/%25%25"},
+		{"http://host/%2525252525252525", "host/%25"},
+		{"http://host/asdf%25%32%35asd", "host/asdf%25asd"},
+		{"http://host/%%%25%32%35asd%%", "host/%25%25%25asd%25%25"},
+		{"http://www.google.com/", "www.google.com/"},
+		{"http://%31%36%38%2e%31%38%38%2e%39%39%2e%32%36/%2E%73%65%63%75%72%65/%77%77%77%2E%65%62%61%79%2E%63%6F%6D/", "168.188.99.26/.secure/www.ebay.com/"},
+		{"http://195.127.0.11/uploads/%20%20%20%20/.verify/.eBaysecure=updateuserdataxplimnbqmn-xplmvalidateinfoswqpcmlx=hgplmcx/", "195.127.0.11/uploads/%20%20%20%20/.verify/.eBaysecure=updateuserdataxplimnbqmn-xplmvalidateinfoswqpcmlx=hgplmcx/"},
+		{"http://host%23.com/%257Ea%2521b%2540c%2523d%2524e%25f%255E00%252611%252A22%252833%252944_55%252B", "host%23.com/~a!b@c%23d$e%25f^00&11*22(33)44_55+"},
+		{"http://3279880203/blah", "195.127.0.11/blah"},
+		{"http://www.google.com/blah/..", "www.google.com/"},
+		{"www.google.com/", "www.google.com/"},
+		{"www.google.com", "www.google.com/"},
+		{"http://www.evil.com/blah#frag", "www.evil.com/blah"},
+		{"http://www.GOOgle.com/", "www.google.com/"},
+		{"http://www.google.com.../", "www.google.com/"},
+		{"http://www.google.com/foo\tbar\rbaz\n2", "www.google.com/foobarbaz2"},
+		{"http://www.google.com/q?", "www.google.com/q?"},
+		{"http://www.google.com/q?r?", "www.google.com/q?r?"},
+		{"http://www.google.com/q?r?s", "www.google.com/q?r?s"},
+		{"http://evil.com/foo#bar#baz", "evil.com/foo"},
+		{"http://evil.com/foo;", "evil.com/foo;"},
+		{"http://evil.com/foo?bar;", "evil.com/foo?bar;"},
+		{"http://\x01\x80.com/", "%01%80.com/"},
+		{"http://notrailingslash.com", "notrailingslash.com/"},
+		{"http://www.gotaport.com:1234/", "www.gotaport.com/"},
+		{"  http://www.google.com/  ", "www.google.com/"},
+		{"http:// leadingspace.com/", "%20leadingspace.com/"},
+		{"http://%20leadingspace.com/", "%20leadingspace.com/"},
+		{"%20leadingspace.com/", "%20leadingspace.com/"},
+		{"https://www.securesite.com/", "www.securesite.com/"},
+		{"http://host.com/ab%23cd", "host.com/ab%23cd"},
+		{"http://host.com//twoslashes?more//slashes", "host.com/twoslashes?more//slashes"},
+	}
+	for _, tt := range tests {
+		host, path, err := Canonicalize(tt.in)
+		if err != nil {
+			t.Errorf("Canonicalize(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if got := host + path; got != tt.want {
+			t.Errorf("Canonicalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCanonicalizeErrors(t *testing.T) {
+	for _, in := range []string{"", "http://", "://example.com/", "http:///path"} {
+		if host, path, err := Canonicalize(in); err == nil {
+			t.Errorf("Canonicalize(%q) = %q, %q, want an error", in, host, path)
+		}
+	}
+}
+
+// The suffix/prefix examples of the Safe Browsing API documentation.
+func TestExpressions(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"http://a.b.c/1/2.html?param=1", []string{
+			"a.b.c/1/2.html?param=1",
+			"a.b.c/1/2.html",
+			"a.b.c/",
+			"a.b.c/1/",
+			"b.c/1/2.html?param=1",
+			"b.c/1/2.html",
+			"b.c/",
+			"b.c/1/",
+		}},
+		{"http://a.b.c.d.e.f.g/1.html", []string{
+			"a.b.c.d.e.f.g/1.html",
+			"a.b.c.d.e.f.g/",
+			"c.d.e.f.g/1.html",
+			"c.d.e.f.g/",
+			"d.e.f.g/1.html",
+			"d.e.f.g/",
+			"e.f.g/1.html",
+			"e.f.g/",
+			"f.g/1.html",
+			"f.g/",
+		}},
+		{"http://1.2.3.4/1/", []string{
+			"1.2.3.4/1/",
+			"1.2.3.4/",
+		}},
+		{"http://a.b/1/2/3/4/5/6.html", []string{
+			"a.b/1/2/3/4/5/6.html",
+			"a.b/",
+			"a.b/1/",
+			"a.b/1/2/",
+			"a.b/1/2/3/",
+		}},
+	}
+	for _, tt := range tests {
+		got, err := Expressions(tt.in)
+		if err != nil {
+			t.Errorf("Expressions(%q) failed: %v", tt.in, err)
+			continue
+		}
+		slices.Sort(got)
+		want := slices.Sorted(slices.Values(tt.want))
+		if !slices.Equal(got, want) {
+			t.Errorf("Expressions(%q) = %q, want %q", tt.in, got, want)
+		}
+	}
+}
+
+func TestParseIPv4(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"1.2.3.4", "1.2.3.4", true},
+		{"3279880203", "195.127.0.11", true},
+		{"0x7f.1", "127.0.0.1", true},
+		{"0x7F000001", "127.0.0.1", true},
+		{"0300.0250.0.1", "192.168.0.1", true},
+		{"017700000001", "127.0.0.1", true},
+		{"10.65535", "10.0.255.255", true},
+		{"10.1.65535", "10.1.255.255", true},
+		{"1.2.3.256", "", false},
+		{"1.2.65536", "", false},
+		{"256.1.1.1", "", false},
+		{"4294967296", "", false},
+		{"08.1.1.1", "", false},
+		{"1.2.3.4.5", "", false},
+		{"1..2.3", "", false},
+		{"example.com", "", false},
+		{"0x7g.0.0.1", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := parseIPv4(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseIPv4(%q) = %q, %v, want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
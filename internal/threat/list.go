@@ -0,0 +1,125 @@
+package threat
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisKey holds the hex encoded hash prefixes shared by all instances.
+const redisKey = "threat:prefixes"
+
+var ErrInvalidPrefix = errors.New("invalid prefix")
+
+// List screens URLs against locally stored SHA-256 hash prefixes of known
+// bad URL expressions, without calling any external service. Prefixes are
+// kept in Redis and mirrored in memory for lookups.
+type List struct {
+	rdb *redis.Client
+
+	mu       sync.RWMutex
+	prefixes map[int]map[string]struct{} // prefix length in bytes -> prefixes
+	count    int
+}
+
+func NewList(rdb *redis.Client) *List {
+	return &List{
+		rdb:      rdb,
+		prefixes: map[int]map[string]struct{}{},
+	}
+}
+
+// Match reports whether any expression of rawURL hashes to a listed prefix,
+// and returns the expression that matched.
+func (l *List) Match(rawURL string) (string, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.count == 0 {
+		return "", false
+	}
+
+	exprs, err := Expressions(rawURL)
+	if err != nil {
+		return "", false
+	}
+	for _, expr := range exprs {
+		sum := sha256.Sum256([]byte(expr))
+		for n, set := range l.prefixes {
+			if _, ok := set[string(sum[:n])]; ok {
+				return expr, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Import stores hex encoded prefixes (4 to 32 bytes). With replace the
+// previous list is dropped first. It returns the size of the new list.
+func (l *List) Import(ctx context.Context, prefixes []string, replace bool) (int, error) {
+	members := make([]any, 0, len(prefixes))
+	for _, p := range prefixes {
+		p = strings.ToLower(strings.TrimSpace(p))
+		b, err := hex.DecodeString(p)
+		if err != nil || len(b) < 4 || len(b) > sha256.Size {
+			return 0, fmt.Errorf("%w %q: want 4 to 32 hex encoded bytes", ErrInvalidPrefix, p)
+		}
+		members = append(members, p)
+	}
+
+	_, err := l.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if replace {
+			pipe.Del(ctx, redisKey)
+		}
+		if len(members) > 0 {
+			pipe.SAdd(ctx, redisKey, members...)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := l.Refresh(ctx); err != nil {
+		return 0, err
+	}
+	return l.Len(), nil
+}
+
+// Refresh reloads the in-memory copy from Redis, picking up imports made
+// through other instances.
+func (l *List) Refresh(ctx context.Context) error {
+	members, err := l.rdb.SMembers(ctx, redisKey).Result()
+	if err != nil {
+		return err
+	}
+
+	prefixes := map[int]map[string]struct{}{}
+	for _, m := range members {
+		b, err := hex.DecodeString(m)
+		if err != nil {
+			continue
+		}
+		if prefixes[len(b)] == nil {
+			prefixes[len(b)] = map[string]struct{}{}
+		}
+		prefixes[len(b)][string(b)] = struct{}{}
+	}
+
+	l.mu.Lock()
+	l.prefixes = prefixes
+	l.count = len(members)
+	l.mu.Unlock()
+	return nil
+}
+
+func (l *List) Len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.count
+}
@@ -0,0 +1,146 @@
+package useragent
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	OSiOS      = "ios"
+	OSAndroid  = "android"
+	OSWindows  = "windows"
+	OSMacOS    = "macos"
+	OSChromeOS = "chromeos"
+	OSLinux    = "linux"
+	OSOther    = "other"
+
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceDesktop = "desktop"
+	DeviceBot     = "bot"
+
+	BrowserChrome  = "chrome"
+	BrowserSafari  = "safari"
+	BrowserFirefox = "firefox"
+	BrowserEdge    = "edge"
+	BrowserOpera   = "opera"
+	BrowserSamsung = "samsung"
+	BrowserOther   = "other"
+)
+
+var (
+	OSes     = []string{OSiOS, OSAndroid, OSWindows, OSMacOS, OSChromeOS, OSLinux, OSOther}
+	Devices  = []string{DeviceMobile, DeviceTablet, DeviceDesktop, DeviceBot}
+	Browsers = []string{BrowserChrome, BrowserSafari, BrowserFirefox, BrowserEdge, BrowserOpera, BrowserSamsung, BrowserOther}
+)
+
+// Agent is what we care about from a User-Agent header.
+type Agent struct {
+	OS      string
+	Device  string
+	Browser string
+}
+
+// botTokens identify crawlers, link unfurlers and HTTP libraries. Names
+// ending in "bot" are matched as whole words by isBot.
+var botTokens = []string{
+	"crawler", "spider", "slurp", "preview", "facebookexternalhit",
+	"whatsapp", "telegram", "skypeuripreview", "embedly", "vkshare",
+	"curl/", "wget/", "python-requests", "python-urllib", "go-http-client",
+	"okhttp", "java/", "libwww", "httpclient", "headless",
+}
+
+// Parse classifies a User-Agent header. It only looks for well known
+// tokens, so it is cheap and good enough for routing, but not exact.
+func Parse(ua string) Agent {
+	lower := strings.ToLower(ua)
+	return Agent{
+		OS:      parseOS(lower),
+		Device:  parseDevice(lower),
+		Browser: parseBrowser(lower),
+	}
+}
+
+func parseOS(ua string) string {
+	switch {
+	case containsAny(ua, "iphone", "ipad", "ipod"):
+		return OSiOS
+	case strings.Contains(ua, "android"):
+		return OSAndroid
+	case strings.Contains(ua, "windows"):
+		return OSWindows
+	case strings.Contains(ua, "cros"):
+		return OSChromeOS
+	case containsAny(ua, "macintosh", "mac os x"):
+		return OSMacOS
+	case strings.Contains(ua, "linux"):
+		return OSLinux
+	default:
+		return OSOther
+	}
+}
+
+func parseDevice(ua string) string {
+	switch {
+	case isBot(ua):
+		return DeviceBot
+	case containsAny(ua, "ipad", "tablet", "kindle", "silk/") ||
+		(strings.Contains(ua, "android") && !strings.Contains(ua, "mobile")):
+		return DeviceTablet
+	case containsAny(ua, "mobi", "iphone", "ipod", "android", "windows phone"):
+		return DeviceMobile
+	default:
+		return DeviceDesktop
+	}
+}
+
+// parseBrowser checks the most specific tokens first, since nearly every
+// browser also claims to be Safari and most claim to be Chrome.
+func parseBrowser(ua string) string {
+	switch {
+	case containsAny(ua, "edg/", "edge/", "edgios", "edga/"):
+		return BrowserEdge
+	case containsAny(ua, "opr/", "opera", "opios"):
+		return BrowserOpera
+	case strings.Contains(ua, "samsungbrowser"):
+		return BrowserSamsung
+	case containsAny(ua, "firefox/", "fxios"):
+		return BrowserFirefox
+	case containsAny(ua, "chrome/", "crios", "chromium"):
+		return BrowserChrome
+	case strings.Contains(ua, "safari/"):
+		return BrowserSafari
+	default:
+		return BrowserOther
+	}
+}
+
+// notBots are words ending in "bot" that aren't bots, like the phone maker
+// in "Android 10; CUBOT X30".
+var notBots = map[string]bool{
+	"cubot": true,
+}
+
+// isBot looks for botTokens and for words like Googlebot or
+// Slackbot-LinkExpanding.
+func isBot(ua string) bool {
+	if containsAny(ua, botTokens...) {
+		return true
+	}
+	words := strings.FieldsFunc(ua, func(r rune) bool { return !unicode.IsLetter(r) })
+	for _, word := range words {
+		if strings.HasSuffix(word, "bot") && !notBots[word] {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAny(s string, substrs ...string) bool {
+	for _, sub := range substrs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
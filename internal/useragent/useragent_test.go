@@ -0,0 +1,126 @@
+package useragent
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		ua   string
+		want Agent
+	}{
+		{
+			"chrome on windows",
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			Agent{OSWindows, DeviceDesktop, BrowserChrome},
+		},
+		{
+			"edge on windows",
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91",
+			Agent{OSWindows, DeviceDesktop, BrowserEdge},
+		},
+		{
+			"safari on macos",
+			"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Safari/605.1.15",
+			Agent{OSMacOS, DeviceDesktop, BrowserSafari},
+		},
+		{
+			"firefox on linux",
+			"Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+			Agent{OSLinux, DeviceDesktop, BrowserFirefox},
+		},
+		{
+			"chrome on chromeos",
+			"Mozilla/5.0 (X11; CrOS x86_64 14541.0.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			Agent{OSChromeOS, DeviceDesktop, BrowserChrome},
+		},
+		{
+			"safari on iphone",
+			"Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1",
+			Agent{OSiOS, DeviceMobile, BrowserSafari},
+		},
+		{
+			"chrome on iphone",
+			"Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/120.0.6099.119 Mobile/15E148 Safari/604.1",
+			Agent{OSiOS, DeviceMobile, BrowserChrome},
+		},
+		{
+			"firefox on iphone",
+			"Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) FxiOS/121.0 Mobile/15E148 Safari/605.1.15",
+			Agent{OSiOS, DeviceMobile, BrowserFirefox},
+		},
+		{
+			"safari on ipad",
+			"Mozilla/5.0 (iPad; CPU OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1",
+			Agent{OSiOS, DeviceTablet, BrowserSafari},
+		},
+		{
+			"chrome on android phone",
+			"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.144 Mobile Safari/537.36",
+			Agent{OSAndroid, DeviceMobile, BrowserChrome},
+		},
+		{
+			"chrome on android tablet",
+			"Mozilla/5.0 (Linux; Android 13; SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.144 Safari/537.36",
+			Agent{OSAndroid, DeviceTablet, BrowserChrome},
+		},
+		{
+			"samsung internet",
+			"Mozilla/5.0 (Linux; Android 14; SM-S918B) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/23.0 Chrome/115.0.0.0 Mobile Safari/537.36",
+			Agent{OSAndroid, DeviceMobile, BrowserSamsung},
+		},
+		{
+			"opera on android",
+			"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36 OPR/79.0.4195.76550",
+			Agent{OSAndroid, DeviceMobile, BrowserOpera},
+		},
+		{
+			"kindle fire",
+			"Mozilla/5.0 (Linux; Android 9; KFTRWI) AppleWebKit/537.36 (KHTML, like Gecko) Silk/120.3.1 like Chrome/120.0.6099.230 Safari/537.36",
+			Agent{OSAndroid, DeviceTablet, BrowserChrome},
+		},
+		{
+			"googlebot",
+			"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			Agent{OSOther, DeviceBot, BrowserOther},
+		},
+		{
+			"googlebot smartphone",
+			"Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.216 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			Agent{OSAndroid, DeviceBot, BrowserChrome},
+		},
+		{"slack unfurler", "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)", Agent{OSOther, DeviceBot, BrowserOther}},
+		{"facebook", "facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)", Agent{OSOther, DeviceBot, BrowserOther}},
+		{"whatsapp", "WhatsApp/2.23.20.0 A", Agent{OSOther, DeviceBot, BrowserOther}},
+		{"telegram", "TelegramBot (like TwitterBot)", Agent{OSOther, DeviceBot, BrowserOther}},
+		{"curl", "curl/8.5.0", Agent{OSOther, DeviceBot, BrowserOther}},
+		{"python", "python-requests/2.31.0", Agent{OSOther, DeviceBot, BrowserOther}},
+		{"go", "Go-http-client/1.1", Agent{OSOther, DeviceBot, BrowserOther}},
+		{
+			"headless chrome",
+			"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) HeadlessChrome/120.0.0.0 Safari/537.36",
+			Agent{OSLinux, DeviceBot, BrowserChrome},
+		},
+		{"bingbot", "Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)", Agent{OSOther, DeviceBot, BrowserOther}},
+		{"discord", "Mozilla/5.0 (compatible; Discordbot/2.0; +https://discordapp.com)", Agent{OSOther, DeviceBot, BrowserOther}},
+		{
+			"cubot phone",
+			"Mozilla/5.0 (Linux; Android 10; CUBOT X30) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
+			Agent{OSAndroid, DeviceMobile, BrowserChrome},
+		},
+		{
+			"robot in a model name",
+			"Mozilla/5.0 (Linux; Android 12; Robotics Tab) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			Agent{OSAndroid, DeviceTablet, BrowserChrome},
+		},
+		{"empty", "", Agent{OSOther, DeviceDesktop, BrowserOther}},
+		{"blank", "   ", Agent{OSOther, DeviceDesktop, BrowserOther}},
+		{"unknown", "SomeApp/1.0", Agent{OSOther, DeviceDesktop, BrowserOther}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.ua); got != tt.want {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.ua, got, tt.want)
+			}
+		})
+	}
+}
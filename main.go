@@ -3,24 +3,33 @@ package main
 import (
 	"context"
 	"crypto/tls"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/joho/godotenv"
 	"github.com/redis/go-redis/v9"
+	"github.com/robfig/cron/v3"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/reflection"
 
+	"tinyurl/internal/clientip"
+	"tinyurl/internal/geoip"
+	"tinyurl/internal/health"
+	"tinyurl/internal/policy"
+	"tinyurl/internal/ratelimit"
 	"tinyurl/internal/service"
+	"tinyurl/internal/threat"
 	pb "tinyurl/proto/tinyurl/v1"
 )
 
@@ -28,10 +37,49 @@ var (
 	ServerURL            = "http://localhost:7860"
 	RateLimitMax         = 10
 	RateLimitWindows     = 1 * time.Minute
-	ExlusiveLinkExp  int = 24 // in hours
+	AnonymousLinkExp int = 24 // in hours
+
+	// Destination health checks, a link counts as broken after
+	// HealthBrokenAfter failed checks in a row
+	HealthCheckSchedule = "@every 6h"
+	HealthBrokenAfter   = 3
+
+	// Expired links answer 410 Gone and keep their alias reserved this long
+	TombstoneTTL = service.DefaultTombstoneTTL
+
+	// TimeZone runs the cron jobs and reads the schedules of links that
+	// don't have a time zone of their own
+	TimeZone = "Asia/Jakarta"
+
+	// RouteRateLimits are applied per client IP. Shorten is limited by the
+	// caller's plan instead.
+	RouteRateLimits = map[string]ratelimit.Limit{
+		pb.TinyURL_GetOriginal_FullMethodName:     {Rate: 120, Window: time.Minute},
+		pb.TinyURL_GetChallenge_FullMethodName:    {Rate: 30, Window: time.Minute},
+		pb.TinyURL_ReportLink_FullMethodName:      {Rate: 5, Window: 10 * time.Minute},
+		pb.TinyURL_GetLink_FullMethodName:         {Rate: 60, Window: time.Minute},
+		pb.TinyURL_ListBrokenLinks_FullMethodName: {Rate: 10, Window: time.Minute},
+		pb.TinyURL_GetLinkStats_FullMethodName:    {Rate: 30, Window: time.Minute},
+		pb.TinyURL_UnlockLink_FullMethodName:      {Rate: 10, Window: time.Minute},
+		pb.TinyURL_PreviewLink_FullMethodName:     {Rate: 30, Window: time.Minute},
+		pb.TinyURL_UpdateLink_FullMethodName:      {Rate: 30, Window: time.Minute},
+
+		// Admin RPCs, limited so leaked or guessed keys can't hammer them
+		pb.TinyURL_ReloadPolicies_FullMethodName:   {Rate: 10, Window: time.Minute},
+		pb.TinyURL_GetPolicyStatus_FullMethodName:  {Rate: 30, Window: time.Minute},
+		pb.TinyURL_ImportThreatList_FullMethodName: {Rate: 10, Window: time.Minute},
+		pb.TinyURL_ListReports_FullMethodName:      {Rate: 30, Window: time.Minute},
+		pb.TinyURL_ResolveReport_FullMethodName:    {Rate: 30, Window: time.Minute},
+		pb.TinyURL_DisableLink_FullMethodName:      {Rate: 30, Window: time.Minute},
+		pb.TinyURL_DeleteLink_FullMethodName:       {Rate: 30, Window: time.Minute},
+		pb.TinyURL_BulkUpdateLinks_FullMethodName:  {Rate: 5, Window: time.Minute},
+	}
 )
 
 var rdb *redis.Client
+var plans *service.Plans
+var limiter *ratelimit.Limiter
+var clientIPs *clientip.Resolver
 var ctx = context.Background()
 
 func main() {
@@ -48,13 +96,52 @@ func main() {
 		redisPassword = ""
 	}
 
+	var err error
+
 	serverURL := os.Getenv("SERVER_URL")
 	if serverURL != "" {
 		ServerURL = serverURL
 	}
 
 	if exclusiveLinkExp := os.Getenv("EXCLUSIVE_LINK_EXP"); exclusiveLinkExp != "" {
-		ExlusiveLinkExp, _ = strconv.Atoi(exclusiveLinkExp)
+		AnonymousLinkExp, _ = strconv.Atoi(exclusiveLinkExp)
+	}
+
+	if tombstoneTTL := os.Getenv("TOMBSTONE_TTL"); tombstoneTTL != "" {
+		hours, _ := strconv.Atoi(tombstoneTTL)
+		TombstoneTTL = time.Duration(hours) * time.Hour
+	}
+
+	if timeZone := os.Getenv("TIME_ZONE"); timeZone != "" {
+		TimeZone = timeZone
+	}
+
+	if schedule := os.Getenv("HEALTH_CHECK_SCHEDULE"); schedule != "" {
+		HealthCheckSchedule = schedule
+	}
+	if brokenAfter := os.Getenv("HEALTH_BROKEN_AFTER"); brokenAfter != "" {
+		HealthBrokenAfter, _ = strconv.Atoi(brokenAfter)
+	}
+
+	// Loopback is always trusted, the gateway and redirect handler reach the
+	// gRPC server through it
+	trustedProxies := clientip.DefaultTrustedProxies
+	if v := os.Getenv("TRUSTED_PROXIES"); v != "" {
+		trustedProxies = append(trustedProxies, strings.Split(v, ",")...)
+	}
+	clientIPs, err = clientip.NewResolver(trustedProxies, os.Getenv("PROXY_HEADER"))
+	if err != nil {
+		fmt.Println("Error parsing TRUSTED_PROXIES:", err)
+		return
+	}
+
+	plans = service.DefaultPlans(AnonymousLinkExp, RateLimitMax, RateLimitWindows)
+	if plansFile := os.Getenv("PLANS_FILE"); plansFile != "" {
+		if err := plans.LoadFile(plansFile); err != nil {
+			fmt.Println("Error loading plans:", err)
+			return
+		}
+		fmt.Println("Loaded plans from", plansFile)
 	}
 
 	redisOptions := &redis.Options{
@@ -77,12 +164,24 @@ func main() {
 		fmt.Println("Connected to Redis")
 	}
 
+	threats := threat.NewList(rdb)
+	if err := threats.Refresh(ctx); err != nil {
+		fmt.Println("Error loading threat list:", err)
+	}
+
 	// scheduler
-	scheduller := NewScheduller()
+	location := loadTimeZone(TimeZone)
+	scheduller := NewScheduller(location)
 	defer scheduller.Stop()
 	scheduller.AddFunc("@daily", func() {
 		fmt.Println("Running heartbeat job")
 	})
+	// Pick up threat list imports made through other instances
+	scheduller.AddFunc("@every 10m", func() {
+		if err := threats.Refresh(ctx); err != nil {
+			fmt.Println("Error refreshing threat list:", err)
+		}
+	})
 	go scheduller.Start()
 
 	// --- gRPC Server Setup ---
@@ -92,10 +191,79 @@ func main() {
 		return
 	}
 
-	grpcServer := grpc.NewServer()
-	tinyURLService := service.NewTinyURLService(rdb, ServerURL, ExlusiveLinkExp)
+	limiter = ratelimit.New(rdb)
+	guard := NewEnumerationGuard(rdb, limiter)
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			guard.UnaryServerInterceptor(clientIPs.FromContext),
+			ratelimit.UnaryServerInterceptor(limiter, rateLimitRule),
+		),
+		grpc.StreamInterceptor(ratelimit.StreamServerInterceptor(limiter, rateLimitRule)),
+	)
+	var pow *service.ProofOfWork
+	if os.Getenv("POW_ENABLED") == "true" {
+		pow = service.NewProofOfWork(rdb, os.Getenv("POW_SECRET"))
+		fmt.Println("Proof-of-work enabled for anonymous link creation")
+	}
+
+	// Domain policy, reloaded when the file changes or on SIGHUP
+	policies := policy.New(os.Getenv("POLICY_FILE"))
+	if policyFile := os.Getenv("POLICY_FILE"); policyFile != "" {
+		if err := policies.Reload(); err != nil {
+			fmt.Println("Error loading domain policy:", err)
+			return
+		}
+
+		stopWatch := make(chan struct{})
+		defer close(stopWatch)
+		go policies.Watch(5*time.Second, stopWatch)
+
+		hup := make(chan os.Signal, 1)
+		signal.Notify(hup, syscall.SIGHUP)
+		go func() {
+			for range hup {
+				if err := policies.Reload(); err != nil {
+					fmt.Println("Failed to reload domain policy:", err)
+				}
+			}
+		}()
+	}
+
+	tinyURLService := service.NewTinyURLService(rdb, ServerURL, plans, pow, policies, threats)
+	tinyURLService.TombstoneTTL = TombstoneTTL
+	tinyURLService.ClientIPs = clientIPs
+	tinyURLService.TimeZone = location
+	if secret := os.Getenv("UNLOCK_SECRET"); secret != "" {
+		tinyURLService.UnlockSecret = []byte(secret)
+	}
+
+	// Offline GeoIP database for country targeting rules
+	if geoipFile := os.Getenv("GEOIP_DATABASE"); geoipFile != "" {
+		db, err := geoip.Open(geoipFile)
+		if err != nil {
+			fmt.Println("Error loading GeoIP database:", err)
+			return
+		}
+		tinyURLService.GeoIP = db
+		fmt.Println("GeoIP database loaded from", geoipFile)
+	}
 	pb.RegisterTinyURLServer(grpcServer, tinyURLService)
 
+	// Check link destinations in the background, skipping a run while the
+	// previous one is still going
+	checker := health.NewChecker()
+	healthJob := cron.NewChain(cron.SkipIfStillRunning(cron.DefaultLogger)).Then(cron.FuncJob(func() {
+		if err := tinyURLService.CheckLinkHealth(ctx, checker, HealthBrokenAfter); err != nil {
+			fmt.Println("Error checking link health:", err)
+		}
+	}))
+	if HealthCheckSchedule != "off" {
+		if _, err := scheduller.AddJob(HealthCheckSchedule, healthJob); err != nil {
+			fmt.Println("Invalid HEALTH_CHECK_SCHEDULE:", err)
+			return
+		}
+	}
+
 	// Register reflection service
 	reflection.Register(grpcServer)
 
@@ -116,7 +284,10 @@ func main() {
 	}
 	defer conn.Close()
 
-	gwmux := runtime.NewServeMux()
+	gwmux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(incomingHeaderMatcher),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeaderMatcher),
+	)
 	// Register the handler (translates REST to gRPC)
 	err = pb.RegisterTinyURLHandler(ctx, gwmux, conn)
 	if err != nil {
@@ -133,7 +304,17 @@ func main() {
 	// We need to distinguish between GET / (index.html) and GET /SHORTCODE (redirect).
 
 	// Create a client for the redirect handler to use
-	grpcClient := pb.NewTinyURLClient(conn)
+	redirect := &RedirectHandler{
+		client: pb.NewTinyURLClient(conn),
+	}
+
+	// Visitors report abusive links through a form posting to /v1/reports
+	mux.HandleFunc("GET /report", func(w http.ResponseWriter, r *http.Request) {
+		renderPage(w, http.StatusOK, "report.html", map[string]string{
+			"Title":     "Report a link",
+			"ShortCode": r.URL.Query().Get("code"),
+		})
+	})
 
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		// If path is exactly "/", serve index.html
@@ -144,37 +325,19 @@ func main() {
 
 		// Check if it is /tinyurl (Gateway should handle this, but mux logic is specific)
 		// Since we registered gateway separately, we need to route specific paths to it.
-		// The gateway handles /tinyurl (POST) and /v1/url/{code} (GET).
-		if strings.HasPrefix(r.URL.Path, "/tinyurl") || strings.HasPrefix(r.URL.Path, "/v1/url") {
+		// The gateway handles /tinyurl (POST) and everything under /v1/.
+		if strings.HasPrefix(r.URL.Path, "/tinyurl") || strings.HasPrefix(r.URL.Path, "/v1/") {
 			gwmux.ServeHTTP(w, r)
 			return
 		}
 
 		// Otherwise, treat as Short Code Redirect
-		// GET /{shortCode}
-		shortCode := strings.TrimPrefix(r.URL.Path, "/")
-		if shortCode == "" {
-			http.ServeFile(w, r, "index.html") // Fallback
-			return
-		}
-
-		// Call gRPC GetOriginal
-		resp, err := grpcClient.GetOriginal(ctx, &pb.GetOriginalRequest{ShortCode: shortCode})
-		if err != nil {
-			// Handle error (Not Found, etc)
-			// gRPC error codes need to be mapped if we want specific HTTP statuses,
-			// but for now 404 or 500 is fine.
-			fmt.Printf("Redirect error for %s: %v\n", shortCode, err)
-			http.Error(w, "URL not found", http.StatusNotFound)
-			return
-		}
-
-		http.Redirect(w, r, resp.LongUrl, http.StatusSeeOther)
+		redirect.ServeHTTP(w, r)
 	})
 
 	server := &http.Server{
 		Addr:    ":7860",
-		Handler: corsMiddleware(rateLimitMiddleware(mux)),
+		Handler: corsMiddleware(clientIPMiddleware(mux)),
 	}
 
 	fmt.Println("Server starting on :7860 (HTTP Gateway + Redirect)")
@@ -191,6 +354,7 @@ func corsMiddleware(next http.Handler) http.Handler {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
 		w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+		w.Header().Set("Access-Control-Expose-Headers", "X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset, Retry-After")
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
@@ -201,44 +365,70 @@ func corsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-// Updating Rate Limit to use new logic or just same path check
-func rateLimitMiddleware(next http.Handler) http.Handler {
+// clientIPMiddleware replaces the forwarding headers with the resolved client
+// IP, so the gateway passes a chain to gRPC that no longer contains anything
+// the client could have spoofed.
+func clientIPMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Update path check: Gateway exposes /tinyurl
-		if r.URL.Path == "/tinyurl" && r.Method == "POST" {
-			ip := getRealIP(r)
-			key := "rate_limit:" + ip
-
-			count, err := rdb.Incr(ctx, key).Result()
-			if err != nil {
-				fmt.Println("Redis error:", err)
-				next.ServeHTTP(w, r)
-				return
-			}
+		r.Header.Set("X-Forwarded-For", getRealIP(r))
+		r.Header.Del("Forwarded")
+		next.ServeHTTP(w, r)
+	})
+}
 
-			if count == 1 {
-				rdb.Expire(ctx, key, RateLimitWindows)
-			}
+// rateLimitRule picks the bucket for a gRPC call. Shorten is limited per API
+// key (or per IP for anonymous callers) using the caller's plan, every other
+// route uses RouteRateLimits per IP.
+func rateLimitRule(ctx context.Context, fullMethod string) (string, ratelimit.Limit, bool, error) {
+	ip := clientIPs.FromContext(ctx)
 
-			if count > int64(RateLimitMax) {
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusTooManyRequests)
-				json.NewEncoder(w).Encode(map[string]string{
-					"error":   "Too many requests",
-					"message": "Try again after 1 minute.",
-				})
-				return
-			}
+	if fullMethod == pb.TinyURL_Shorten_FullMethodName {
+		key := "rate_limit:" + fullMethod + ":ip:" + ip
+		caller, err := plans.CallerFromContext(ctx)
+		if err != nil {
+			// Unknown keys take from the anonymous bucket of the IP, so
+			// guessing keys is limited too. Shorten rejects them after.
+			caller, _ = plans.Resolve("")
+		} else if caller.Authenticated() {
+			key = "rate_limit:" + fullMethod + ":key:" + caller.APIKey
 		}
-		next.ServeHTTP(w, r)
-	})
+		return key, ratelimit.Limit{Rate: caller.Plan.RateLimit, Window: caller.Plan.RateLimitWindow()}, true, nil
+	}
+
+	limit, ok := RouteRateLimits[fullMethod]
+	if !ok {
+		return "", ratelimit.Limit{}, false, nil
+	}
+	return "rate_limit:" + fullMethod + ":ip:" + ip, limit, true, nil
 }
 
-func getRealIP(r *http.Request) string {
-	xfwd := r.Header.Get("X-Forwarded-For")
-	if xfwd != "" {
-		ips := strings.Split(xfwd, ",")
-		return strings.TrimSpace(ips[0])
+// incomingHeaderMatcher passes the Referer to the service the same way the
+// redirect handler does, everything else as the gateway does by default.
+func incomingHeaderMatcher(key string) (string, bool) {
+	if http.CanonicalHeaderKey(key) == "Referer" {
+		return service.RefererMetadata, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// outgoingHeaderMatcher exposes the rate limit metadata as plain HTTP headers,
+// everything else keeps the gateway's Grpc-Metadata- prefix.
+func outgoingHeaderMatcher(key string) (string, bool) {
+	switch key {
+	case ratelimit.HeaderLimit, ratelimit.HeaderRemaining, ratelimit.HeaderReset, ratelimit.HeaderRetryAfter:
+		return key, true
 	}
-	return r.RemoteAddr
+	return runtime.MetadataHeaderPrefix + key, true
+}
+
+func copyRateLimitHeaders(w http.ResponseWriter, md metadata.MD) {
+	for _, key := range []string{ratelimit.HeaderLimit, ratelimit.HeaderRemaining, ratelimit.HeaderReset, ratelimit.HeaderRetryAfter} {
+		if v := md.Get(key); len(v) > 0 {
+			w.Header().Set(key, v[0])
+		}
+	}
+}
+
+func getRealIP(r *http.Request) string {
+	return clientIPs.FromRequest(r)
 }
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"net/http"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// pages are the HTML pages served instead of a redirect, each one rendered
+// inside templates/layout.html.
+var pages = map[string]*template.Template{}
+
+func init() {
+	entries, err := templateFS.ReadDir("templates")
+	if err != nil {
+		panic(err)
+	}
+	for _, entry := range entries {
+		if entry.Name() == "layout.html" {
+			continue
+		}
+		pages[entry.Name()] = template.Must(template.ParseFS(templateFS, "templates/layout.html", "templates/"+entry.Name()))
+	}
+}
+
+func renderPage(w http.ResponseWriter, code int, name string, data any) {
+	var buf bytes.Buffer
+	if err := pages[name].ExecuteTemplate(&buf, "layout", data); err != nil {
+		fmt.Printf("Failed to render %s: %v\n", name, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(code)
+	buf.WriteTo(w)
+}
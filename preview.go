@@ -0,0 +1,81 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"tinyurl/internal/service"
+	pb "tinyurl/proto/tinyurl/v1"
+)
+
+// PreviewSuffix appended to a short code shows its preview page instead of
+// redirecting, like /AbC123xYz0+.
+const PreviewSuffix = "+"
+
+var safetyNames = map[pb.LinkSafety]string{
+	pb.LinkSafety_LINK_SAFETY_OK:      "ok",
+	pb.LinkSafety_LINK_SAFETY_FLAGGED: "flagged",
+	pb.LinkSafety_LINK_SAFETY_BLOCKED: "blocked",
+}
+
+// preview renders where a short link goes without following it. Continue
+// leads to the short URL itself, or to longURL when the visitor was already
+// resolved by GetOriginal for links that always show the preview.
+func (h *RedirectHandler) preview(w http.ResponseWriter, r *http.Request, shortCode, ip, longURL string) {
+	var header metadata.MD
+	callCtx := metadata.AppendToOutgoingContext(r.Context(), "x-forwarded-for", ip, service.RefererMetadata, r.Referer())
+	resp, err := h.client.PreviewLink(callCtx, &pb.PreviewLinkRequest{
+		ShortCode: shortCode,
+	}, grpc.Header(&header))
+	copyRateLimitHeaders(w, header)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	data := map[string]any{
+		"Title":        "Link preview",
+		"ShortCode":    shortCode,
+		"LongURL":      resp.LongUrl,
+		"Domain":       resp.Domain,
+		"Clicks":       resp.Clicks,
+		"Safety":       safetyNames[resp.Safety],
+		"Warning":      resp.Warning,
+		"Broken":       resp.Broken,
+		"Targeted":     resp.Targeted,
+		"Limited":      resp.Limited,
+		"Interstitial": longURL != "",
+		"ContinueURL":  "/" + shortCode,
+		"CreatedAt":    time.Time{},
+	}
+	if resp.CreatedAt > 0 {
+		data["CreatedAt"] = time.Unix(resp.CreatedAt, 0).UTC()
+	}
+	// The visitor got past the password and used a click to get here, so
+	// the destination is theirs to see
+	if longURL != "" {
+		data["ContinueURL"] = longURL
+		data["LongURL"] = longURL
+		if u, err := url.Parse(longURL); err == nil {
+			data["Domain"] = u.Hostname()
+		}
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	renderPage(w, http.StatusOK, "preview.html", data)
+}
+
+// previewCode returns the code of a /{code}+ or /preview/{code} path.
+func previewCode(shortCode, extraPath string) (string, bool) {
+	if shortCode == "preview" {
+		code := strings.TrimPrefix(extraPath, "/")
+		return code, code != "" && !strings.Contains(code, "/")
+	}
+	code, ok := strings.CutSuffix(shortCode, PreviewSuffix)
+	return code, ok && code != "" && extraPath == ""
+}
@@ -10,6 +10,7 @@ import (
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -22,29 +23,3050 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// QueryPassthrough decides what happens to the query string of a short URL.
+type QueryPassthrough int32
+
+const (
+	QueryPassthrough_QUERY_PASSTHROUGH_DISABLED     QueryPassthrough = 0 // The query is dropped
+	QueryPassthrough_QUERY_PASSTHROUGH_REQUEST_WINS QueryPassthrough = 1 // Merged, parameters of the request replace those of the destination
+	QueryPassthrough_QUERY_PASSTHROUGH_LINK_WINS    QueryPassthrough = 2 // Merged, parameters already in the destination are kept
+)
+
+// Enum value maps for QueryPassthrough.
+var (
+	QueryPassthrough_name = map[int32]string{
+		0: "QUERY_PASSTHROUGH_DISABLED",
+		1: "QUERY_PASSTHROUGH_REQUEST_WINS",
+		2: "QUERY_PASSTHROUGH_LINK_WINS",
+	}
+	QueryPassthrough_value = map[string]int32{
+		"QUERY_PASSTHROUGH_DISABLED":     0,
+		"QUERY_PASSTHROUGH_REQUEST_WINS": 1,
+		"QUERY_PASSTHROUGH_LINK_WINS":    2,
+	}
+)
+
+func (x QueryPassthrough) Enum() *QueryPassthrough {
+	p := new(QueryPassthrough)
+	*p = x
+	return p
+}
+
+func (x QueryPassthrough) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (QueryPassthrough) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_tinyurl_v1_tinyurl_proto_enumTypes[0].Descriptor()
+}
+
+func (QueryPassthrough) Type() protoreflect.EnumType {
+	return &file_proto_tinyurl_v1_tinyurl_proto_enumTypes[0]
+}
+
+func (x QueryPassthrough) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use QueryPassthrough.Descriptor instead.
+func (QueryPassthrough) EnumDescriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{0}
+}
+
+// RedirectType is how visitors are sent to the destination.
+type RedirectType int32
+
+const (
+	RedirectType_REDIRECT_TYPE_UNSPECIFIED  RedirectType = 0 // Same as REDIRECT_TYPE_303
+	RedirectType_REDIRECT_TYPE_301          RedirectType = 1 // Moved Permanently, cached by browsers
+	RedirectType_REDIRECT_TYPE_302          RedirectType = 2 // Found
+	RedirectType_REDIRECT_TYPE_303          RedirectType = 3 // See Other
+	RedirectType_REDIRECT_TYPE_307          RedirectType = 4 // Temporary Redirect, keeps the method and body
+	RedirectType_REDIRECT_TYPE_308          RedirectType = 5 // Permanent Redirect, keeps the method and body, cached by browsers
+	RedirectType_REDIRECT_TYPE_META_REFRESH RedirectType = 6 // Branded page redirecting with <meta http-equiv="refresh">
+	RedirectType_REDIRECT_TYPE_JAVASCRIPT   RedirectType = 7 // Branded page redirecting with JavaScript
+)
+
+// Enum value maps for RedirectType.
+var (
+	RedirectType_name = map[int32]string{
+		0: "REDIRECT_TYPE_UNSPECIFIED",
+		1: "REDIRECT_TYPE_301",
+		2: "REDIRECT_TYPE_302",
+		3: "REDIRECT_TYPE_303",
+		4: "REDIRECT_TYPE_307",
+		5: "REDIRECT_TYPE_308",
+		6: "REDIRECT_TYPE_META_REFRESH",
+		7: "REDIRECT_TYPE_JAVASCRIPT",
+	}
+	RedirectType_value = map[string]int32{
+		"REDIRECT_TYPE_UNSPECIFIED":  0,
+		"REDIRECT_TYPE_301":          1,
+		"REDIRECT_TYPE_302":          2,
+		"REDIRECT_TYPE_303":          3,
+		"REDIRECT_TYPE_307":          4,
+		"REDIRECT_TYPE_308":          5,
+		"REDIRECT_TYPE_META_REFRESH": 6,
+		"REDIRECT_TYPE_JAVASCRIPT":   7,
+	}
+)
+
+func (x RedirectType) Enum() *RedirectType {
+	p := new(RedirectType)
+	*p = x
+	return p
+}
+
+func (x RedirectType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RedirectType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_tinyurl_v1_tinyurl_proto_enumTypes[1].Descriptor()
+}
+
+func (RedirectType) Type() protoreflect.EnumType {
+	return &file_proto_tinyurl_v1_tinyurl_proto_enumTypes[1]
+}
+
+func (x RedirectType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RedirectType.Descriptor instead.
+func (RedirectType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{1}
+}
+
+type ReportAction int32
+
+const (
+	ReportAction_REPORT_ACTION_UNSPECIFIED ReportAction = 0
+	ReportAction_REPORT_ACTION_DISMISS     ReportAction = 1
+	ReportAction_REPORT_ACTION_DISABLE     ReportAction = 2
+	ReportAction_REPORT_ACTION_DELETE      ReportAction = 3
+)
+
+// Enum value maps for ReportAction.
+var (
+	ReportAction_name = map[int32]string{
+		0: "REPORT_ACTION_UNSPECIFIED",
+		1: "REPORT_ACTION_DISMISS",
+		2: "REPORT_ACTION_DISABLE",
+		3: "REPORT_ACTION_DELETE",
+	}
+	ReportAction_value = map[string]int32{
+		"REPORT_ACTION_UNSPECIFIED": 0,
+		"REPORT_ACTION_DISMISS":     1,
+		"REPORT_ACTION_DISABLE":     2,
+		"REPORT_ACTION_DELETE":      3,
+	}
+)
+
+func (x ReportAction) Enum() *ReportAction {
+	p := new(ReportAction)
+	*p = x
+	return p
+}
+
+func (x ReportAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ReportAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_tinyurl_v1_tinyurl_proto_enumTypes[2].Descriptor()
+}
+
+func (ReportAction) Type() protoreflect.EnumType {
+	return &file_proto_tinyurl_v1_tinyurl_proto_enumTypes[2]
+}
+
+func (x ReportAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ReportAction.Descriptor instead.
+func (ReportAction) EnumDescriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{2}
+}
+
+type BulkAction int32
+
+const (
+	BulkAction_BULK_ACTION_UNSPECIFIED BulkAction = 0
+	BulkAction_BULK_ACTION_DISABLE     BulkAction = 1
+	BulkAction_BULK_ACTION_DELETE      BulkAction = 2
+	BulkAction_BULK_ACTION_REPOINT     BulkAction = 3
+)
+
+// Enum value maps for BulkAction.
+var (
+	BulkAction_name = map[int32]string{
+		0: "BULK_ACTION_UNSPECIFIED",
+		1: "BULK_ACTION_DISABLE",
+		2: "BULK_ACTION_DELETE",
+		3: "BULK_ACTION_REPOINT",
+	}
+	BulkAction_value = map[string]int32{
+		"BULK_ACTION_UNSPECIFIED": 0,
+		"BULK_ACTION_DISABLE":     1,
+		"BULK_ACTION_DELETE":      2,
+		"BULK_ACTION_REPOINT":     3,
+	}
+)
+
+func (x BulkAction) Enum() *BulkAction {
+	p := new(BulkAction)
+	*p = x
+	return p
+}
+
+func (x BulkAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BulkAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_tinyurl_v1_tinyurl_proto_enumTypes[3].Descriptor()
+}
+
+func (BulkAction) Type() protoreflect.EnumType {
+	return &file_proto_tinyurl_v1_tinyurl_proto_enumTypes[3]
+}
+
+func (x BulkAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BulkAction.Descriptor instead.
+func (BulkAction) EnumDescriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{3}
+}
+
+// LinkSafety is what we know about the destination of a link.
+type LinkSafety int32
+
+const (
+	LinkSafety_LINK_SAFETY_UNSPECIFIED LinkSafety = 0
+	LinkSafety_LINK_SAFETY_OK          LinkSafety = 1 // Nothing known against it
+	LinkSafety_LINK_SAFETY_FLAGGED     LinkSafety = 2 // Matched the threat list, visitors see a warning first
+	LinkSafety_LINK_SAFETY_BLOCKED     LinkSafety = 3 // Its domain is no longer allowed, the link doesn't redirect
+)
+
+// Enum value maps for LinkSafety.
+var (
+	LinkSafety_name = map[int32]string{
+		0: "LINK_SAFETY_UNSPECIFIED",
+		1: "LINK_SAFETY_OK",
+		2: "LINK_SAFETY_FLAGGED",
+		3: "LINK_SAFETY_BLOCKED",
+	}
+	LinkSafety_value = map[string]int32{
+		"LINK_SAFETY_UNSPECIFIED": 0,
+		"LINK_SAFETY_OK":          1,
+		"LINK_SAFETY_FLAGGED":     2,
+		"LINK_SAFETY_BLOCKED":     3,
+	}
+)
+
+func (x LinkSafety) Enum() *LinkSafety {
+	p := new(LinkSafety)
+	*p = x
+	return p
+}
+
+func (x LinkSafety) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LinkSafety) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_tinyurl_v1_tinyurl_proto_enumTypes[4].Descriptor()
+}
+
+func (LinkSafety) Type() protoreflect.EnumType {
+	return &file_proto_tinyurl_v1_tinyurl_proto_enumTypes[4]
+}
+
+func (x LinkSafety) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LinkSafety.Descriptor instead.
+func (LinkSafety) EnumDescriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{4}
+}
+
 type ShortenRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	LongUrl          string                 `protobuf:"bytes,1,opt,name=long_url,proto3" json:"long_url,omitempty"`
+	ShortCode        string                 `protobuf:"bytes,2,opt,name=short_code,proto3" json:"short_code,omitempty"`              // Optional custom alias
+	ExpiresInHours   int32                  `protobuf:"varint,3,opt,name=expires_in_hours,proto3" json:"expires_in_hours,omitempty"` // Optional, capped by the caller's plan
+	PowChallenge     string                 `protobuf:"bytes,4,opt,name=pow_challenge,proto3" json:"pow_challenge,omitempty"`        // Required for anonymous callers when proof-of-work is enabled
+	PowNonce         string                 `protobuf:"bytes,5,opt,name=pow_nonce,proto3" json:"pow_nonce,omitempty"`
+	FallbackUrls     []string               `protobuf:"bytes,6,rep,name=fallback_urls,proto3" json:"fallback_urls,omitempty"`                                                                              // Optional, tried in order when the destination is down
+	LastResortUrl    string                 `protobuf:"bytes,7,opt,name=last_resort_url,proto3" json:"last_resort_url,omitempty"`                                                                          // Optional, used when every destination is down
+	RedirectType     RedirectType           `protobuf:"varint,8,opt,name=redirect_type,proto3,enum=tinyurl.v1.RedirectType" json:"redirect_type,omitempty"`                                                // Optional, defaults to 303
+	PathPassthrough  bool                   `protobuf:"varint,9,opt,name=path_passthrough,proto3" json:"path_passthrough,omitempty"`                                                                       // Append extra path segments of the short URL to the destination
+	QueryPassthrough QueryPassthrough       `protobuf:"varint,10,opt,name=query_passthrough,proto3,enum=tinyurl.v1.QueryPassthrough" json:"query_passthrough,omitempty"`                                   // Merge the query of the short URL into the destination
+	ParamPatterns    map[string]string      `protobuf:"bytes,11,rep,name=param_patterns,proto3" json:"param_patterns,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Regular expressions the values of template placeholders must match
+	Rules            []*TargetRule          `protobuf:"bytes,12,rep,name=rules,proto3" json:"rules,omitempty"`                                                                                             // Optional, the first matching rule replaces long_url
+	Variants         []*Variant             `protobuf:"bytes,13,rep,name=variants,proto3" json:"variants,omitempty"`                                                                                       // Optional, splits visitors no rule matched between weighted destinations
+	NotBefore        int64                  `protobuf:"varint,14,opt,name=not_before,proto3" json:"not_before,omitempty"`                                                                                  // Optional, Unix seconds. Until then visitors see a countdown
+	TimeZone         string                 `protobuf:"bytes,15,opt,name=time_zone,proto3" json:"time_zone,omitempty"`                                                                                     // Optional IANA time zone for schedules, defaults to the server's
+	Schedules        []*Schedule            `protobuf:"bytes,16,rep,name=schedules,proto3" json:"schedules,omitempty"`                                                                                     // Optional, the first active window replaces long_url for visitors no rule matched
+	Password         string                 `protobuf:"bytes,17,opt,name=password,proto3" json:"password,omitempty"`                                                                                       // Optional, visitors have to enter it before being redirected
+	MaxClicks        int32                  `protobuf:"varint,18,opt,name=max_clicks,proto3" json:"max_clicks,omitempty"`                                                                                  // Optional, the link expires after this many visits. Bots and previews don't count
+	Access           *AccessPolicy          `protobuf:"bytes,19,opt,name=access,proto3" json:"access,omitempty"`                                                                                           // Optional, limits who can follow the link
+	Interstitial     bool                   `protobuf:"varint,20,opt,name=interstitial,proto3" json:"interstitial,omitempty"`                                                                              // Optional, always show the preview page with a button instead of redirecting
+	Template         bool                   `protobuf:"varint,21,opt,name=template,proto3" json:"template,omitempty"`                                                                                      // Fill {1} or {id} placeholders in the destinations from the path after the code
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ShortenRequest) Reset() {
+	*x = ShortenRequest{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShortenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortenRequest) ProtoMessage() {}
+
+func (x *ShortenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortenRequest.ProtoReflect.Descriptor instead.
+func (*ShortenRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ShortenRequest) GetLongUrl() string {
+	if x != nil {
+		return x.LongUrl
+	}
+	return ""
+}
+
+func (x *ShortenRequest) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *ShortenRequest) GetExpiresInHours() int32 {
+	if x != nil {
+		return x.ExpiresInHours
+	}
+	return 0
+}
+
+func (x *ShortenRequest) GetPowChallenge() string {
+	if x != nil {
+		return x.PowChallenge
+	}
+	return ""
+}
+
+func (x *ShortenRequest) GetPowNonce() string {
+	if x != nil {
+		return x.PowNonce
+	}
+	return ""
+}
+
+func (x *ShortenRequest) GetFallbackUrls() []string {
+	if x != nil {
+		return x.FallbackUrls
+	}
+	return nil
+}
+
+func (x *ShortenRequest) GetLastResortUrl() string {
+	if x != nil {
+		return x.LastResortUrl
+	}
+	return ""
+}
+
+func (x *ShortenRequest) GetRedirectType() RedirectType {
+	if x != nil {
+		return x.RedirectType
+	}
+	return RedirectType_REDIRECT_TYPE_UNSPECIFIED
+}
+
+func (x *ShortenRequest) GetPathPassthrough() bool {
+	if x != nil {
+		return x.PathPassthrough
+	}
+	return false
+}
+
+func (x *ShortenRequest) GetQueryPassthrough() QueryPassthrough {
+	if x != nil {
+		return x.QueryPassthrough
+	}
+	return QueryPassthrough_QUERY_PASSTHROUGH_DISABLED
+}
+
+func (x *ShortenRequest) GetParamPatterns() map[string]string {
+	if x != nil {
+		return x.ParamPatterns
+	}
+	return nil
+}
+
+func (x *ShortenRequest) GetRules() []*TargetRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *ShortenRequest) GetVariants() []*Variant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+func (x *ShortenRequest) GetNotBefore() int64 {
+	if x != nil {
+		return x.NotBefore
+	}
+	return 0
+}
+
+func (x *ShortenRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *ShortenRequest) GetSchedules() []*Schedule {
+	if x != nil {
+		return x.Schedules
+	}
+	return nil
+}
+
+func (x *ShortenRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *ShortenRequest) GetMaxClicks() int32 {
+	if x != nil {
+		return x.MaxClicks
+	}
+	return 0
+}
+
+func (x *ShortenRequest) GetAccess() *AccessPolicy {
+	if x != nil {
+		return x.Access
+	}
+	return nil
+}
+
+func (x *ShortenRequest) GetInterstitial() bool {
+	if x != nil {
+		return x.Interstitial
+	}
+	return false
+}
+
+func (x *ShortenRequest) GetTemplate() bool {
+	if x != nil {
+		return x.Template
+	}
+	return false
+}
+
+// AccessPolicy limits who can follow a link, other visitors get 403. Deny
+// wins over allow, and empty lists don't restrict anything.
+type AccessPolicy struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	AllowIps       []string               `protobuf:"bytes,1,rep,name=allow_ips,proto3" json:"allow_ips,omitempty"` // CIDR prefixes or addresses, e.g. "10.8.0.0/16"
+	DenyIps        []string               `protobuf:"bytes,2,rep,name=deny_ips,proto3" json:"deny_ips,omitempty"`
+	AllowReferrers []string               `protobuf:"bytes,3,rep,name=allow_referrers,proto3" json:"allow_referrers,omitempty"` // Domains like "partner.com" or "*.partner.com". Visitors without a referrer are blocked
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *AccessPolicy) Reset() {
+	*x = AccessPolicy{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccessPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccessPolicy) ProtoMessage() {}
+
+func (x *AccessPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccessPolicy.ProtoReflect.Descriptor instead.
+func (*AccessPolicy) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AccessPolicy) GetAllowIps() []string {
+	if x != nil {
+		return x.AllowIps
+	}
+	return nil
+}
+
+func (x *AccessPolicy) GetDenyIps() []string {
+	if x != nil {
+		return x.DenyIps
+	}
+	return nil
+}
+
+func (x *AccessPolicy) GetAllowReferrers() []string {
+	if x != nil {
+		return x.AllowReferrers
+	}
+	return nil
+}
+
+// Schedule is a window in the link's time zone during which visitors are
+// sent to url. Every condition that is set has to hold.
+type Schedule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Days          []string               `protobuf:"bytes,1,rep,name=days,proto3" json:"days,omitempty"`             // mon, tue, ..., sun. Every day if empty
+	StartTime     string                 `protobuf:"bytes,2,opt,name=start_time,proto3" json:"start_time,omitempty"` // "HH:MM", the start of the day if empty
+	EndTime       string                 `protobuf:"bytes,3,opt,name=end_time,proto3" json:"end_time,omitempty"`     // "HH:MM", exclusive, the end of the day if empty. Before start_time to run past midnight
+	StartDate     string                 `protobuf:"bytes,4,opt,name=start_date,proto3" json:"start_date,omitempty"` // "YYYY-MM-DD", inclusive
+	EndDate       string                 `protobuf:"bytes,5,opt,name=end_date,proto3" json:"end_date,omitempty"`     // "YYYY-MM-DD", inclusive
+	Url           string                 `protobuf:"bytes,6,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Schedule) Reset() {
+	*x = Schedule{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Schedule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Schedule) ProtoMessage() {}
+
+func (x *Schedule) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Schedule.ProtoReflect.Descriptor instead.
+func (*Schedule) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Schedule) GetDays() []string {
+	if x != nil {
+		return x.Days
+	}
+	return nil
+}
+
+func (x *Schedule) GetStartTime() string {
+	if x != nil {
+		return x.StartTime
+	}
+	return ""
+}
+
+func (x *Schedule) GetEndTime() string {
+	if x != nil {
+		return x.EndTime
+	}
+	return ""
+}
+
+func (x *Schedule) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *Schedule) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *Schedule) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+// Variant is one destination of an A/B split. Visitors are assigned a
+// variant with a probability proportional to its weight and keep it.
+type Variant struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // Letters, digits, '-' and '_', used in stats. Defaults to "a", "b", ...
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Weight        int32                  `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"` // 0 pauses the variant for new visitors
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Variant) Reset() {
+	*x = Variant{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Variant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Variant) ProtoMessage() {}
+
+func (x *Variant) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Variant.ProtoReflect.Descriptor instead.
+func (*Variant) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Variant) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Variant) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Variant) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+// TargetRule sends visitors matching every non-empty condition to url.
+// Each condition lists the values it accepts.
+type TargetRule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Os            []string               `protobuf:"bytes,1,rep,name=os,proto3" json:"os,omitempty"`           // ios, android, windows, macos, chromeos, linux, other
+	Device        []string               `protobuf:"bytes,2,rep,name=device,proto3" json:"device,omitempty"`   // mobile, tablet, desktop, bot
+	Browser       []string               `protobuf:"bytes,3,rep,name=browser,proto3" json:"browser,omitempty"` // chrome, safari, firefox, edge, opera, samsung, other
+	Url           string                 `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
+	Country       []string               `protobuf:"bytes,5,rep,name=country,proto3" json:"country,omitempty"`   // ISO 3166-1 codes like "ID", or the groups "EU", "EEA" and "ASEAN"
+	Language      []string               `protobuf:"bytes,6,rep,name=language,proto3" json:"language,omitempty"` // Preferred language of the visitor, e.g. "id" or "pt-BR"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TargetRule) Reset() {
+	*x = TargetRule{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TargetRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TargetRule) ProtoMessage() {}
+
+func (x *TargetRule) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TargetRule.ProtoReflect.Descriptor instead.
+func (*TargetRule) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TargetRule) GetOs() []string {
+	if x != nil {
+		return x.Os
+	}
+	return nil
+}
+
+func (x *TargetRule) GetDevice() []string {
+	if x != nil {
+		return x.Device
+	}
+	return nil
+}
+
+func (x *TargetRule) GetBrowser() []string {
+	if x != nil {
+		return x.Browser
+	}
+	return nil
+}
+
+func (x *TargetRule) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *TargetRule) GetCountry() []string {
+	if x != nil {
+		return x.Country
+	}
+	return nil
+}
+
+func (x *TargetRule) GetLanguage() []string {
+	if x != nil {
+		return x.Language
+	}
+	return nil
+}
+
+type ShortenResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortUrl      string                 `protobuf:"bytes,1,opt,name=short_url,proto3" json:"short_url,omitempty"`
+	LongUrl       string                 `protobuf:"bytes,2,opt,name=long_url,proto3" json:"long_url,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	ElapsedTime   string                 `protobuf:"bytes,4,opt,name=elapsed_time,proto3" json:"elapsed_time,omitempty"`
+	Plan          string                 `protobuf:"bytes,5,opt,name=plan,proto3" json:"plan,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShortenResponse) Reset() {
+	*x = ShortenResponse{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShortenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortenResponse) ProtoMessage() {}
+
+func (x *ShortenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortenResponse.ProtoReflect.Descriptor instead.
+func (*ShortenResponse) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ShortenResponse) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *ShortenResponse) GetLongUrl() string {
+	if x != nil {
+		return x.LongUrl
+	}
+	return ""
+}
+
+func (x *ShortenResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ShortenResponse) GetElapsedTime() string {
+	if x != nil {
+		return x.ElapsedTime
+	}
+	return ""
+}
+
+func (x *ShortenResponse) GetPlan() string {
+	if x != nil {
+		return x.Plan
+	}
+	return ""
+}
+
+type GetOriginalRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode      string                 `protobuf:"bytes,1,opt,name=short_code,proto3" json:"short_code,omitempty"`
+	Path           string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`                       // Path after the short code, e.g. "/docs/intro" for /abc123/docs/intro
+	Query          string                 `protobuf:"bytes,3,opt,name=query,proto3" json:"query,omitempty"`                     // Raw query string of the short URL
+	UserAgent      string                 `protobuf:"bytes,4,opt,name=user_agent,proto3" json:"user_agent,omitempty"`           // User-Agent of the visitor, for targeting rules
+	AcceptLanguage string                 `protobuf:"bytes,5,opt,name=accept_language,proto3" json:"accept_language,omitempty"` // Accept-Language of the visitor, for targeting rules
+	Variant        string                 `protobuf:"bytes,6,opt,name=variant,proto3" json:"variant,omitempty"`                 // Variant the visitor was assigned before, e.g. from a cookie
+	UnlockToken    string                 `protobuf:"bytes,7,opt,name=unlock_token,proto3" json:"unlock_token,omitempty"`       // From UnlockLink, required for password protected links
+	Preview        bool                   `protobuf:"varint,8,opt,name=preview,proto3" json:"preview,omitempty"`                // HEAD or prefetch request, which doesn't use up a click. Links with a click limit answer it without long_url
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetOriginalRequest) Reset() {
+	*x = GetOriginalRequest{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOriginalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOriginalRequest) ProtoMessage() {}
+
+func (x *GetOriginalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOriginalRequest.ProtoReflect.Descriptor instead.
+func (*GetOriginalRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetOriginalRequest) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *GetOriginalRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *GetOriginalRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *GetOriginalRequest) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *GetOriginalRequest) GetAcceptLanguage() string {
+	if x != nil {
+		return x.AcceptLanguage
+	}
+	return ""
+}
+
+func (x *GetOriginalRequest) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
+func (x *GetOriginalRequest) GetUnlockToken() string {
+	if x != nil {
+		return x.UnlockToken
+	}
+	return ""
+}
+
+func (x *GetOriginalRequest) GetPreview() bool {
+	if x != nil {
+		return x.Preview
+	}
+	return false
+}
+
+type GetOriginalResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LongUrl       string                 `protobuf:"bytes,1,opt,name=long_url,proto3" json:"long_url,omitempty"` // Empty for bots and previews of links with a click limit
+	Flagged       bool                   `protobuf:"varint,2,opt,name=flagged,proto3" json:"flagged,omitempty"`  // Destination matched the threat list, show a warning instead of redirecting
+	Warning       string                 `protobuf:"bytes,3,opt,name=warning,proto3" json:"warning,omitempty"`
+	Fallback      bool                   `protobuf:"varint,4,opt,name=fallback,proto3" json:"fallback,omitempty"`          // The destination is down and long_url is a fallback
+	Rule          int32                  `protobuf:"varint,7,opt,name=rule,proto3" json:"rule,omitempty"`                  // 1-based index of the targeting rule that matched, 0 if none
+	Targeted      bool                   `protobuf:"varint,8,opt,name=targeted,proto3" json:"targeted,omitempty"`          // The link has targeting rules or variants, so the destination depends on the visitor
+	Variant       string                 `protobuf:"bytes,9,opt,name=variant,proto3" json:"variant,omitempty"`             // Variant the visitor was assigned, to be sent back on the next visit
+	Protected     bool                   `protobuf:"varint,10,opt,name=protected,proto3" json:"protected,omitempty"`       // The link is password protected, so the redirect must not be cached
+	Limited       bool                   `protobuf:"varint,11,opt,name=limited,proto3" json:"limited,omitempty"`           // The link has a click limit, so the redirect must not be cached
+	Interstitial  bool                   `protobuf:"varint,12,opt,name=interstitial,proto3" json:"interstitial,omitempty"` // Show the preview page instead of redirecting
+	RedirectType  RedirectType           `protobuf:"varint,5,opt,name=redirect_type,proto3,enum=tinyurl.v1.RedirectType" json:"redirect_type,omitempty"`
+	ExpiresAt     int64                  `protobuf:"varint,6,opt,name=expires_at,proto3" json:"expires_at,omitempty"` // Unix seconds, 0 if it never expires. Only set for permanent redirect types
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOriginalResponse) Reset() {
+	*x = GetOriginalResponse{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOriginalResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOriginalResponse) ProtoMessage() {}
+
+func (x *GetOriginalResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOriginalResponse.ProtoReflect.Descriptor instead.
+func (*GetOriginalResponse) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetOriginalResponse) GetLongUrl() string {
+	if x != nil {
+		return x.LongUrl
+	}
+	return ""
+}
+
+func (x *GetOriginalResponse) GetFlagged() bool {
+	if x != nil {
+		return x.Flagged
+	}
+	return false
+}
+
+func (x *GetOriginalResponse) GetWarning() string {
+	if x != nil {
+		return x.Warning
+	}
+	return ""
+}
+
+func (x *GetOriginalResponse) GetFallback() bool {
+	if x != nil {
+		return x.Fallback
+	}
+	return false
+}
+
+func (x *GetOriginalResponse) GetRule() int32 {
+	if x != nil {
+		return x.Rule
+	}
+	return 0
+}
+
+func (x *GetOriginalResponse) GetTargeted() bool {
+	if x != nil {
+		return x.Targeted
+	}
+	return false
+}
+
+func (x *GetOriginalResponse) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
+func (x *GetOriginalResponse) GetProtected() bool {
+	if x != nil {
+		return x.Protected
+	}
+	return false
+}
+
+func (x *GetOriginalResponse) GetLimited() bool {
+	if x != nil {
+		return x.Limited
+	}
+	return false
+}
+
+func (x *GetOriginalResponse) GetInterstitial() bool {
+	if x != nil {
+		return x.Interstitial
+	}
+	return false
+}
+
+func (x *GetOriginalResponse) GetRedirectType() RedirectType {
+	if x != nil {
+		return x.RedirectType
+	}
+	return RedirectType_REDIRECT_TYPE_UNSPECIFIED
+}
+
+func (x *GetOriginalResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+type GetChallengeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetChallengeRequest) Reset() {
+	*x = GetChallengeRequest{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetChallengeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChallengeRequest) ProtoMessage() {}
+
+func (x *GetChallengeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChallengeRequest.ProtoReflect.Descriptor instead.
+func (*GetChallengeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{8}
+}
+
+type GetChallengeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Required      bool                   `protobuf:"varint,1,opt,name=required,proto3" json:"required,omitempty"`
+	Challenge     string                 `protobuf:"bytes,2,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	Difficulty    int32                  `protobuf:"varint,3,opt,name=difficulty,proto3" json:"difficulty,omitempty"` // Leading zero bits required in sha256(challenge + ":" + nonce)
+	ExpiresAt     int64                  `protobuf:"varint,4,opt,name=expires_at,proto3" json:"expires_at,omitempty"` // Unix seconds
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetChallengeResponse) Reset() {
+	*x = GetChallengeResponse{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetChallengeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChallengeResponse) ProtoMessage() {}
+
+func (x *GetChallengeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChallengeResponse.ProtoReflect.Descriptor instead.
+func (*GetChallengeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetChallengeResponse) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+func (x *GetChallengeResponse) GetChallenge() string {
+	if x != nil {
+		return x.Challenge
+	}
+	return ""
+}
+
+func (x *GetChallengeResponse) GetDifficulty() int32 {
+	if x != nil {
+		return x.Difficulty
+	}
+	return 0
+}
+
+func (x *GetChallengeResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+type ReloadPoliciesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReloadPoliciesRequest) Reset() {
+	*x = ReloadPoliciesRequest{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReloadPoliciesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReloadPoliciesRequest) ProtoMessage() {}
+
+func (x *ReloadPoliciesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReloadPoliciesRequest.ProtoReflect.Descriptor instead.
+func (*ReloadPoliciesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{10}
+}
+
+type GetPolicyStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPolicyStatusRequest) Reset() {
+	*x = GetPolicyStatusRequest{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPolicyStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPolicyStatusRequest) ProtoMessage() {}
+
+func (x *GetPolicyStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPolicyStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetPolicyStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{11}
+}
+
+type PolicyStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	LoadedAt      int64                  `protobuf:"varint,2,opt,name=loaded_at,proto3" json:"loaded_at,omitempty"` // Unix seconds, 0 if never loaded
+	AllowRules    int32                  `protobuf:"varint,3,opt,name=allow_rules,proto3" json:"allow_rules,omitempty"`
+	BlockRules    int32                  `protobuf:"varint,4,opt,name=block_rules,proto3" json:"block_rules,omitempty"`
+	DefaultAction string                 `protobuf:"bytes,5,opt,name=default_action,proto3" json:"default_action,omitempty"`
+	LastError     string                 `protobuf:"bytes,6,opt,name=last_error,proto3" json:"last_error,omitempty"` // Error of the last failed reload, the previous rules stay active
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PolicyStatus) Reset() {
+	*x = PolicyStatus{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PolicyStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PolicyStatus) ProtoMessage() {}
+
+func (x *PolicyStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PolicyStatus.ProtoReflect.Descriptor instead.
+func (*PolicyStatus) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *PolicyStatus) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *PolicyStatus) GetLoadedAt() int64 {
+	if x != nil {
+		return x.LoadedAt
+	}
+	return 0
+}
+
+func (x *PolicyStatus) GetAllowRules() int32 {
+	if x != nil {
+		return x.AllowRules
+	}
+	return 0
+}
+
+func (x *PolicyStatus) GetBlockRules() int32 {
+	if x != nil {
+		return x.BlockRules
+	}
+	return 0
+}
+
+func (x *PolicyStatus) GetDefaultAction() string {
+	if x != nil {
+		return x.DefaultAction
+	}
+	return ""
+}
+
+func (x *PolicyStatus) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+type ImportThreatListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prefixes      []string               `protobuf:"bytes,1,rep,name=prefixes,proto3" json:"prefixes,omitempty"` // Hex encoded, 4 to 32 bytes each
+	Replace       bool                   `protobuf:"varint,2,opt,name=replace,proto3" json:"replace,omitempty"`  // Drop the current list first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportThreatListRequest) Reset() {
+	*x = ImportThreatListRequest{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportThreatListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportThreatListRequest) ProtoMessage() {}
+
+func (x *ImportThreatListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportThreatListRequest.ProtoReflect.Descriptor instead.
+func (*ImportThreatListRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ImportThreatListRequest) GetPrefixes() []string {
+	if x != nil {
+		return x.Prefixes
+	}
+	return nil
+}
+
+func (x *ImportThreatListRequest) GetReplace() bool {
+	if x != nil {
+		return x.Replace
+	}
+	return false
+}
+
+type ImportThreatListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Total         int32                  `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"` // Number of prefixes in the list after the import
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportThreatListResponse) Reset() {
+	*x = ImportThreatListResponse{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportThreatListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportThreatListResponse) ProtoMessage() {}
+
+func (x *ImportThreatListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportThreatListResponse.ProtoReflect.Descriptor instead.
+func (*ImportThreatListResponse) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ImportThreatListResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type ReportLinkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,proto3" json:"short_code,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"` // phishing, malware, spam, illegal or other
+	Details       string                 `protobuf:"bytes,3,opt,name=details,proto3" json:"details,omitempty"`
+	Contact       string                 `protobuf:"bytes,4,opt,name=contact,proto3" json:"contact,omitempty"` // Optional, for follow-up questions
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportLinkRequest) Reset() {
+	*x = ReportLinkRequest{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportLinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportLinkRequest) ProtoMessage() {}
+
+func (x *ReportLinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportLinkRequest.ProtoReflect.Descriptor instead.
+func (*ReportLinkRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ReportLinkRequest) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *ReportLinkRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ReportLinkRequest) GetDetails() string {
+	if x != nil {
+		return x.Details
+	}
+	return ""
+}
+
+func (x *ReportLinkRequest) GetContact() string {
+	if x != nil {
+		return x.Contact
+	}
+	return ""
+}
+
+type ReportLinkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportLinkResponse) Reset() {
+	*x = ReportLinkResponse{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportLinkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportLinkResponse) ProtoMessage() {}
+
+func (x *ReportLinkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportLinkResponse.ProtoReflect.Descriptor instead.
+func (*ReportLinkResponse) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ReportLinkResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ReportLinkResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type Report struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ShortCode     string                 `protobuf:"bytes,2,opt,name=short_code,proto3" json:"short_code,omitempty"`
+	LongUrl       string                 `protobuf:"bytes,3,opt,name=long_url,proto3" json:"long_url,omitempty"` // Destination at the time of the report
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	Details       string                 `protobuf:"bytes,5,opt,name=details,proto3" json:"details,omitempty"`
+	Contact       string                 `protobuf:"bytes,6,opt,name=contact,proto3" json:"contact,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,7,opt,name=created_at,proto3" json:"created_at,omitempty"` // Unix seconds
+	Status        string                 `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`          // open or resolved
+	Resolution    string                 `protobuf:"bytes,9,opt,name=resolution,proto3" json:"resolution,omitempty"`  // dismissed, disabled or deleted
+	Note          string                 `protobuf:"bytes,10,opt,name=note,proto3" json:"note,omitempty"`
+	ResolvedAt    int64                  `protobuf:"varint,11,opt,name=resolved_at,proto3" json:"resolved_at,omitempty"` // Unix seconds
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Report) Reset() {
+	*x = Report{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Report) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Report) ProtoMessage() {}
+
+func (x *Report) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Report.ProtoReflect.Descriptor instead.
+func (*Report) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Report) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Report) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *Report) GetLongUrl() string {
+	if x != nil {
+		return x.LongUrl
+	}
+	return ""
+}
+
+func (x *Report) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Report) GetDetails() string {
+	if x != nil {
+		return x.Details
+	}
+	return ""
+}
+
+func (x *Report) GetContact() string {
+	if x != nil {
+		return x.Contact
+	}
+	return ""
+}
+
+func (x *Report) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *Report) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Report) GetResolution() string {
+	if x != nil {
+		return x.Resolution
+	}
+	return ""
+}
+
+func (x *Report) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *Report) GetResolvedAt() int64 {
+	if x != nil {
+		return x.ResolvedAt
+	}
+	return 0
+}
+
+type ListReportsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"` // open (default) or resolved
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`  // Default 50, max 500
+	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReportsRequest) Reset() {
+	*x = ListReportsRequest{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReportsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReportsRequest) ProtoMessage() {}
+
+func (x *ListReportsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReportsRequest.ProtoReflect.Descriptor instead.
+func (*ListReportsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListReportsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListReportsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListReportsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type ListReportsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reports       []*Report              `protobuf:"bytes,1,rep,name=reports,proto3" json:"reports,omitempty"`
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReportsResponse) Reset() {
+	*x = ListReportsResponse{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReportsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReportsResponse) ProtoMessage() {}
+
+func (x *ListReportsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReportsResponse.ProtoReflect.Descriptor instead.
+func (*ListReportsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListReportsResponse) GetReports() []*Report {
+	if x != nil {
+		return x.Reports
+	}
+	return nil
+}
+
+func (x *ListReportsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type ResolveReportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Action        ReportAction           `protobuf:"varint,2,opt,name=action,proto3,enum=tinyurl.v1.ReportAction" json:"action,omitempty"`
+	Note          string                 `protobuf:"bytes,3,opt,name=note,proto3" json:"note,omitempty"`    // Shown on the disabled page when disabling
+	Legal         bool                   `protobuf:"varint,4,opt,name=legal,proto3" json:"legal,omitempty"` // Disabled for legal reasons, served as 451 instead of 410
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveReportRequest) Reset() {
+	*x = ResolveReportRequest{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveReportRequest) ProtoMessage() {}
+
+func (x *ResolveReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveReportRequest.ProtoReflect.Descriptor instead.
+func (*ResolveReportRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ResolveReportRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ResolveReportRequest) GetAction() ReportAction {
+	if x != nil {
+		return x.Action
+	}
+	return ReportAction_REPORT_ACTION_UNSPECIFIED
+}
+
+func (x *ResolveReportRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *ResolveReportRequest) GetLegal() bool {
+	if x != nil {
+		return x.Legal
+	}
+	return false
+}
+
+type DisableLinkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,proto3" json:"short_code,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"` // Shown to visitors
+	Legal         bool                   `protobuf:"varint,3,opt,name=legal,proto3" json:"legal,omitempty"`  // Served as 451 instead of 410
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisableLinkRequest) Reset() {
+	*x = DisableLinkRequest{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisableLinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisableLinkRequest) ProtoMessage() {}
+
+func (x *DisableLinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisableLinkRequest.ProtoReflect.Descriptor instead.
+func (*DisableLinkRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *DisableLinkRequest) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *DisableLinkRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *DisableLinkRequest) GetLegal() bool {
+	if x != nil {
+		return x.Legal
+	}
+	return false
+}
+
+type DisableLinkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisableLinkResponse) Reset() {
+	*x = DisableLinkResponse{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisableLinkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisableLinkResponse) ProtoMessage() {}
+
+func (x *DisableLinkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisableLinkResponse.ProtoReflect.Descriptor instead.
+func (*DisableLinkResponse) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{22}
+}
+
+type DeleteLinkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,proto3" json:"short_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteLinkRequest) Reset() {
+	*x = DeleteLinkRequest{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteLinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteLinkRequest) ProtoMessage() {}
+
+func (x *DeleteLinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteLinkRequest.ProtoReflect.Descriptor instead.
+func (*DeleteLinkRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *DeleteLinkRequest) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+type DeleteLinkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteLinkResponse) Reset() {
+	*x = DeleteLinkResponse{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteLinkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteLinkResponse) ProtoMessage() {}
+
+func (x *DeleteLinkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteLinkResponse.ProtoReflect.Descriptor instead.
+func (*DeleteLinkResponse) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{24}
+}
+
+type BulkUpdateLinksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Patterns      []string               `protobuf:"bytes,1,rep,name=patterns,proto3" json:"patterns,omitempty"` // Domain policy syntax: "example.com", "*.example.com" or "/regex/"
+	Action        BulkAction             `protobuf:"varint,2,opt,name=action,proto3,enum=tinyurl.v1.BulkAction" json:"action,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`             // BULK_ACTION_DISABLE: shown to visitors
+	Legal         bool                   `protobuf:"varint,4,opt,name=legal,proto3" json:"legal,omitempty"`              // BULK_ACTION_DISABLE: served as 451 instead of 410
+	NewLongUrl    string                 `protobuf:"bytes,5,opt,name=new_long_url,proto3" json:"new_long_url,omitempty"` // BULK_ACTION_REPOINT: the new destination
+	DryRun        bool                   `protobuf:"varint,6,opt,name=dry_run,proto3" json:"dry_run,omitempty"`          // Only report the links that would be affected
+	BatchSize     int32                  `protobuf:"varint,7,opt,name=batch_size,proto3" json:"batch_size,omitempty"`    // Default 100, max 1000
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkUpdateLinksRequest) Reset() {
+	*x = BulkUpdateLinksRequest{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkUpdateLinksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkUpdateLinksRequest) ProtoMessage() {}
+
+func (x *BulkUpdateLinksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkUpdateLinksRequest.ProtoReflect.Descriptor instead.
+func (*BulkUpdateLinksRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *BulkUpdateLinksRequest) GetPatterns() []string {
+	if x != nil {
+		return x.Patterns
+	}
+	return nil
+}
+
+func (x *BulkUpdateLinksRequest) GetAction() BulkAction {
+	if x != nil {
+		return x.Action
+	}
+	return BulkAction_BULK_ACTION_UNSPECIFIED
+}
+
+func (x *BulkUpdateLinksRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *BulkUpdateLinksRequest) GetLegal() bool {
+	if x != nil {
+		return x.Legal
+	}
+	return false
+}
+
+func (x *BulkUpdateLinksRequest) GetNewLongUrl() string {
+	if x != nil {
+		return x.NewLongUrl
+	}
+	return ""
+}
+
+func (x *BulkUpdateLinksRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *BulkUpdateLinksRequest) GetBatchSize() int32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+type BulkMatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,proto3" json:"short_code,omitempty"`
+	LongUrl       string                 `protobuf:"bytes,2,opt,name=long_url,proto3" json:"long_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkMatch) Reset() {
+	*x = BulkMatch{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkMatch) ProtoMessage() {}
+
+func (x *BulkMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkMatch.ProtoReflect.Descriptor instead.
+func (*BulkMatch) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *BulkMatch) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *BulkMatch) GetLongUrl() string {
+	if x != nil {
+		return x.LongUrl
+	}
+	return ""
+}
+
+type BulkUpdateProgress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Scanned       int64                  `protobuf:"varint,1,opt,name=scanned,proto3" json:"scanned,omitempty"` // Links looked at so far
+	Matched       int64                  `protobuf:"varint,2,opt,name=matched,proto3" json:"matched,omitempty"`
+	Updated       int64                  `protobuf:"varint,3,opt,name=updated,proto3" json:"updated,omitempty"` // Always 0 on a dry run
+	Matches       []*BulkMatch           `protobuf:"bytes,4,rep,name=matches,proto3" json:"matches,omitempty"`  // Links matched in this batch
+	Done          bool                   `protobuf:"varint,5,opt,name=done,proto3" json:"done,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkUpdateProgress) Reset() {
+	*x = BulkUpdateProgress{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkUpdateProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkUpdateProgress) ProtoMessage() {}
+
+func (x *BulkUpdateProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkUpdateProgress.ProtoReflect.Descriptor instead.
+func (*BulkUpdateProgress) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *BulkUpdateProgress) GetScanned() int64 {
+	if x != nil {
+		return x.Scanned
+	}
+	return 0
+}
+
+func (x *BulkUpdateProgress) GetMatched() int64 {
+	if x != nil {
+		return x.Matched
+	}
+	return 0
+}
+
+func (x *BulkUpdateProgress) GetUpdated() int64 {
+	if x != nil {
+		return x.Updated
+	}
+	return 0
+}
+
+func (x *BulkUpdateProgress) GetMatches() []*BulkMatch {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+func (x *BulkUpdateProgress) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+type GetLinkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,proto3" json:"short_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLinkRequest) Reset() {
+	*x = GetLinkRequest{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLinkRequest) ProtoMessage() {}
+
+func (x *GetLinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLinkRequest.ProtoReflect.Descriptor instead.
+func (*GetLinkRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *GetLinkRequest) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+type LinkHealth struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Status              int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"` // HTTP status of the latest check, 0 if the request failed
+	Error               string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	CheckedAt           int64                  `protobuf:"varint,3,opt,name=checked_at,proto3" json:"checked_at,omitempty"` // Unix seconds
+	ConsecutiveFailures int32                  `protobuf:"varint,4,opt,name=consecutive_failures,proto3" json:"consecutive_failures,omitempty"`
+	Broken              bool                   `protobuf:"varint,5,opt,name=broken,proto3" json:"broken,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *LinkHealth) Reset() {
+	*x = LinkHealth{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkHealth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkHealth) ProtoMessage() {}
+
+func (x *LinkHealth) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkHealth.ProtoReflect.Descriptor instead.
+func (*LinkHealth) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *LinkHealth) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *LinkHealth) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *LinkHealth) GetCheckedAt() int64 {
+	if x != nil {
+		return x.CheckedAt
+	}
+	return 0
+}
+
+func (x *LinkHealth) GetConsecutiveFailures() int32 {
+	if x != nil {
+		return x.ConsecutiveFailures
+	}
+	return 0
+}
+
+func (x *LinkHealth) GetBroken() bool {
+	if x != nil {
+		return x.Broken
+	}
+	return false
+}
+
+type LinkInfo struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode         string                 `protobuf:"bytes,1,opt,name=short_code,proto3" json:"short_code,omitempty"`
+	LongUrl           string                 `protobuf:"bytes,2,opt,name=long_url,proto3" json:"long_url,omitempty"`
+	CreatedAt         int64                  `protobuf:"varint,3,opt,name=created_at,proto3" json:"created_at,omitempty"` // Unix seconds
+	ExpiresAt         int64                  `protobuf:"varint,4,opt,name=expires_at,proto3" json:"expires_at,omitempty"` // Unix seconds, 0 if it never expires
+	Owner             string                 `protobuf:"bytes,5,opt,name=owner,proto3" json:"owner,omitempty"`
+	Plan              string                 `protobuf:"bytes,6,opt,name=plan,proto3" json:"plan,omitempty"`
+	Flagged           bool                   `protobuf:"varint,7,opt,name=flagged,proto3" json:"flagged,omitempty"`
+	Disabled          bool                   `protobuf:"varint,8,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	DisabledReason    string                 `protobuf:"bytes,9,opt,name=disabled_reason,proto3" json:"disabled_reason,omitempty"`
+	Health            *LinkHealth            `protobuf:"bytes,10,opt,name=health,proto3" json:"health,omitempty"` // Unset until the first check
+	Fallbacks         []*Fallback            `protobuf:"bytes,11,rep,name=fallbacks,proto3" json:"fallbacks,omitempty"`
+	LastResortUrl     string                 `protobuf:"bytes,12,opt,name=last_resort_url,proto3" json:"last_resort_url,omitempty"`
+	RedirectType      RedirectType           `protobuf:"varint,13,opt,name=redirect_type,proto3,enum=tinyurl.v1.RedirectType" json:"redirect_type,omitempty"`
+	PathPassthrough   bool                   `protobuf:"varint,14,opt,name=path_passthrough,proto3" json:"path_passthrough,omitempty"`
+	QueryPassthrough  QueryPassthrough       `protobuf:"varint,15,opt,name=query_passthrough,proto3,enum=tinyurl.v1.QueryPassthrough" json:"query_passthrough,omitempty"`
+	ParamPatterns     map[string]string      `protobuf:"bytes,16,rep,name=param_patterns,proto3" json:"param_patterns,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Rules             []*TargetRule          `protobuf:"bytes,17,rep,name=rules,proto3" json:"rules,omitempty"`
+	Variants          []*Variant             `protobuf:"bytes,18,rep,name=variants,proto3" json:"variants,omitempty"`
+	NotBefore         int64                  `protobuf:"varint,19,opt,name=not_before,proto3" json:"not_before,omitempty"`
+	TimeZone          string                 `protobuf:"bytes,20,opt,name=time_zone,proto3" json:"time_zone,omitempty"`
+	Schedules         []*Schedule            `protobuf:"bytes,21,rep,name=schedules,proto3" json:"schedules,omitempty"`
+	PasswordProtected bool                   `protobuf:"varint,22,opt,name=password_protected,proto3" json:"password_protected,omitempty"`
+	MaxClicks         int32                  `protobuf:"varint,23,opt,name=max_clicks,proto3" json:"max_clicks,omitempty"` // 0 if unlimited
+	ClicksLeft        int64                  `protobuf:"varint,24,opt,name=clicks_left,proto3" json:"clicks_left,omitempty"`
+	Access            *AccessPolicy          `protobuf:"bytes,25,opt,name=access,proto3" json:"access,omitempty"`
+	Interstitial      bool                   `protobuf:"varint,26,opt,name=interstitial,proto3" json:"interstitial,omitempty"`
+	Template          bool                   `protobuf:"varint,27,opt,name=template,proto3" json:"template,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *LinkInfo) Reset() {
+	*x = LinkInfo{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkInfo) ProtoMessage() {}
+
+func (x *LinkInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkInfo.ProtoReflect.Descriptor instead.
+func (*LinkInfo) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *LinkInfo) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *LinkInfo) GetLongUrl() string {
+	if x != nil {
+		return x.LongUrl
+	}
+	return ""
+}
+
+func (x *LinkInfo) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *LinkInfo) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *LinkInfo) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *LinkInfo) GetPlan() string {
+	if x != nil {
+		return x.Plan
+	}
+	return ""
+}
+
+func (x *LinkInfo) GetFlagged() bool {
+	if x != nil {
+		return x.Flagged
+	}
+	return false
+}
+
+func (x *LinkInfo) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+func (x *LinkInfo) GetDisabledReason() string {
+	if x != nil {
+		return x.DisabledReason
+	}
+	return ""
+}
+
+func (x *LinkInfo) GetHealth() *LinkHealth {
+	if x != nil {
+		return x.Health
+	}
+	return nil
+}
+
+func (x *LinkInfo) GetFallbacks() []*Fallback {
+	if x != nil {
+		return x.Fallbacks
+	}
+	return nil
+}
+
+func (x *LinkInfo) GetLastResortUrl() string {
+	if x != nil {
+		return x.LastResortUrl
+	}
+	return ""
+}
+
+func (x *LinkInfo) GetRedirectType() RedirectType {
+	if x != nil {
+		return x.RedirectType
+	}
+	return RedirectType_REDIRECT_TYPE_UNSPECIFIED
+}
+
+func (x *LinkInfo) GetPathPassthrough() bool {
+	if x != nil {
+		return x.PathPassthrough
+	}
+	return false
+}
+
+func (x *LinkInfo) GetQueryPassthrough() QueryPassthrough {
+	if x != nil {
+		return x.QueryPassthrough
+	}
+	return QueryPassthrough_QUERY_PASSTHROUGH_DISABLED
+}
+
+func (x *LinkInfo) GetParamPatterns() map[string]string {
+	if x != nil {
+		return x.ParamPatterns
+	}
+	return nil
+}
+
+func (x *LinkInfo) GetRules() []*TargetRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *LinkInfo) GetVariants() []*Variant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+func (x *LinkInfo) GetNotBefore() int64 {
+	if x != nil {
+		return x.NotBefore
+	}
+	return 0
+}
+
+func (x *LinkInfo) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *LinkInfo) GetSchedules() []*Schedule {
+	if x != nil {
+		return x.Schedules
+	}
+	return nil
+}
+
+func (x *LinkInfo) GetPasswordProtected() bool {
+	if x != nil {
+		return x.PasswordProtected
+	}
+	return false
+}
+
+func (x *LinkInfo) GetMaxClicks() int32 {
+	if x != nil {
+		return x.MaxClicks
+	}
+	return 0
+}
+
+func (x *LinkInfo) GetClicksLeft() int64 {
+	if x != nil {
+		return x.ClicksLeft
+	}
+	return 0
+}
+
+func (x *LinkInfo) GetAccess() *AccessPolicy {
+	if x != nil {
+		return x.Access
+	}
+	return nil
+}
+
+func (x *LinkInfo) GetInterstitial() bool {
+	if x != nil {
+		return x.Interstitial
+	}
+	return false
+}
+
+func (x *LinkInfo) GetTemplate() bool {
+	if x != nil {
+		return x.Template
+	}
+	return false
+}
+
+type Fallback struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Health        *LinkHealth            `protobuf:"bytes,2,opt,name=health,proto3" json:"health,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Fallback) Reset() {
+	*x = Fallback{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Fallback) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Fallback) ProtoMessage() {}
+
+func (x *Fallback) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Fallback.ProtoReflect.Descriptor instead.
+func (*Fallback) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *Fallback) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Fallback) GetHealth() *LinkHealth {
+	if x != nil {
+		return x.Health
+	}
+	return nil
+}
+
+// LinkSettings are the parts of a link its owner can change.
+type LinkSettings struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	FallbackUrls     []string               `protobuf:"bytes,1,rep,name=fallback_urls,proto3" json:"fallback_urls,omitempty"`
+	LastResortUrl    string                 `protobuf:"bytes,2,opt,name=last_resort_url,proto3" json:"last_resort_url,omitempty"`
+	RedirectType     RedirectType           `protobuf:"varint,3,opt,name=redirect_type,proto3,enum=tinyurl.v1.RedirectType" json:"redirect_type,omitempty"`
+	PathPassthrough  bool                   `protobuf:"varint,4,opt,name=path_passthrough,proto3" json:"path_passthrough,omitempty"`
+	QueryPassthrough QueryPassthrough       `protobuf:"varint,5,opt,name=query_passthrough,proto3,enum=tinyurl.v1.QueryPassthrough" json:"query_passthrough,omitempty"`
+	ParamPatterns    map[string]string      `protobuf:"bytes,6,rep,name=param_patterns,proto3" json:"param_patterns,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Rules            []*TargetRule          `protobuf:"bytes,7,rep,name=rules,proto3" json:"rules,omitempty"`
+	Variants         []*Variant             `protobuf:"bytes,8,rep,name=variants,proto3" json:"variants,omitempty"`
+	NotBefore        int64                  `protobuf:"varint,9,opt,name=not_before,proto3" json:"not_before,omitempty"` // 0 clears it
+	TimeZone         string                 `protobuf:"bytes,10,opt,name=time_zone,proto3" json:"time_zone,omitempty"`
+	Schedules        []*Schedule            `protobuf:"bytes,11,rep,name=schedules,proto3" json:"schedules,omitempty"`
+	Password         string                 `protobuf:"bytes,12,opt,name=password,proto3" json:"password,omitempty"` // Empty removes the password
+	Access           *AccessPolicy          `protobuf:"bytes,13,opt,name=access,proto3" json:"access,omitempty"`     // Unset removes the policy
+	Interstitial     bool                   `protobuf:"varint,14,opt,name=interstitial,proto3" json:"interstitial,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *LinkSettings) Reset() {
+	*x = LinkSettings{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkSettings) ProtoMessage() {}
+
+func (x *LinkSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkSettings.ProtoReflect.Descriptor instead.
+func (*LinkSettings) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *LinkSettings) GetFallbackUrls() []string {
+	if x != nil {
+		return x.FallbackUrls
+	}
+	return nil
+}
+
+func (x *LinkSettings) GetLastResortUrl() string {
+	if x != nil {
+		return x.LastResortUrl
+	}
+	return ""
+}
+
+func (x *LinkSettings) GetRedirectType() RedirectType {
+	if x != nil {
+		return x.RedirectType
+	}
+	return RedirectType_REDIRECT_TYPE_UNSPECIFIED
+}
+
+func (x *LinkSettings) GetPathPassthrough() bool {
+	if x != nil {
+		return x.PathPassthrough
+	}
+	return false
+}
+
+func (x *LinkSettings) GetQueryPassthrough() QueryPassthrough {
+	if x != nil {
+		return x.QueryPassthrough
+	}
+	return QueryPassthrough_QUERY_PASSTHROUGH_DISABLED
+}
+
+func (x *LinkSettings) GetParamPatterns() map[string]string {
+	if x != nil {
+		return x.ParamPatterns
+	}
+	return nil
+}
+
+func (x *LinkSettings) GetRules() []*TargetRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *LinkSettings) GetVariants() []*Variant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+func (x *LinkSettings) GetNotBefore() int64 {
+	if x != nil {
+		return x.NotBefore
+	}
+	return 0
+}
+
+func (x *LinkSettings) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *LinkSettings) GetSchedules() []*Schedule {
+	if x != nil {
+		return x.Schedules
+	}
+	return nil
+}
+
+func (x *LinkSettings) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *LinkSettings) GetAccess() *AccessPolicy {
+	if x != nil {
+		return x.Access
+	}
+	return nil
+}
+
+func (x *LinkSettings) GetInterstitial() bool {
+	if x != nil {
+		return x.Interstitial
+	}
+	return false
+}
+
+type UpdateLinkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,proto3" json:"short_code,omitempty"`
+	Settings      *LinkSettings          `protobuf:"bytes,2,opt,name=settings,proto3" json:"settings,omitempty"`
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=update_mask,proto3" json:"update_mask,omitempty"` // Paths relative to settings, e.g. "fallback_urls"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateLinkRequest) Reset() {
+	*x = UpdateLinkRequest{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateLinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateLinkRequest) ProtoMessage() {}
+
+func (x *UpdateLinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateLinkRequest.ProtoReflect.Descriptor instead.
+func (*UpdateLinkRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *UpdateLinkRequest) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *UpdateLinkRequest) GetSettings() *LinkSettings {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+func (x *UpdateLinkRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type ListBrokenLinksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBrokenLinksRequest) Reset() {
+	*x = ListBrokenLinksRequest{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBrokenLinksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBrokenLinksRequest) ProtoMessage() {}
+
+func (x *ListBrokenLinksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBrokenLinksRequest.ProtoReflect.Descriptor instead.
+func (*ListBrokenLinksRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{34}
+}
+
+type ListBrokenLinksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Links         []*LinkInfo            `protobuf:"bytes,1,rep,name=links,proto3" json:"links,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBrokenLinksResponse) Reset() {
+	*x = ListBrokenLinksResponse{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBrokenLinksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBrokenLinksResponse) ProtoMessage() {}
+
+func (x *ListBrokenLinksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBrokenLinksResponse.ProtoReflect.Descriptor instead.
+func (*ListBrokenLinksResponse) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ListBrokenLinksResponse) GetLinks() []*LinkInfo {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+type UnlockLinkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,proto3" json:"short_code,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlockLinkRequest) Reset() {
+	*x = UnlockLinkRequest{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlockLinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlockLinkRequest) ProtoMessage() {}
+
+func (x *UnlockLinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlockLinkRequest.ProtoReflect.Descriptor instead.
+func (*UnlockLinkRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *UnlockLinkRequest) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *UnlockLinkRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type UnlockLinkResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	LongUrl       string                 `protobuf:"bytes,1,opt,name=long_url,proto3" json:"long_url,omitempty"`
-	ShortCode     string                 `protobuf:"bytes,2,opt,name=short_code,proto3" json:"short_code,omitempty"` // Optional custom alias
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	ExpiresAt     int64                  `protobuf:"varint,2,opt,name=expires_at,proto3" json:"expires_at,omitempty"` // Unix seconds
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ShortenRequest) Reset() {
-	*x = ShortenRequest{}
-	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[0]
+func (x *UnlockLinkResponse) Reset() {
+	*x = UnlockLinkResponse{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ShortenRequest) String() string {
+func (x *UnlockLinkResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ShortenRequest) ProtoMessage() {}
+func (*UnlockLinkResponse) ProtoMessage() {}
 
-func (x *ShortenRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[0]
+func (x *UnlockLinkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -55,50 +3077,47 @@ func (x *ShortenRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ShortenRequest.ProtoReflect.Descriptor instead.
-func (*ShortenRequest) Descriptor() ([]byte, []int) {
-	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use UnlockLinkResponse.ProtoReflect.Descriptor instead.
+func (*UnlockLinkResponse) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{37}
 }
 
-func (x *ShortenRequest) GetLongUrl() string {
+func (x *UnlockLinkResponse) GetToken() string {
 	if x != nil {
-		return x.LongUrl
+		return x.Token
 	}
 	return ""
 }
 
-func (x *ShortenRequest) GetShortCode() string {
+func (x *UnlockLinkResponse) GetExpiresAt() int64 {
 	if x != nil {
-		return x.ShortCode
+		return x.ExpiresAt
 	}
-	return ""
+	return 0
 }
 
-type ShortenResponse struct {
+type PreviewLinkRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ShortUrl      string                 `protobuf:"bytes,1,opt,name=short_url,proto3" json:"short_url,omitempty"`
-	LongUrl       string                 `protobuf:"bytes,2,opt,name=long_url,proto3" json:"long_url,omitempty"`
-	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
-	ElapsedTime   string                 `protobuf:"bytes,4,opt,name=elapsed_time,proto3" json:"elapsed_time,omitempty"`
+	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,proto3" json:"short_code,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ShortenResponse) Reset() {
-	*x = ShortenResponse{}
-	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[1]
+func (x *PreviewLinkRequest) Reset() {
+	*x = PreviewLinkRequest{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ShortenResponse) String() string {
+func (x *PreviewLinkRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ShortenResponse) ProtoMessage() {}
+func (*PreviewLinkRequest) ProtoMessage() {}
 
-func (x *ShortenResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[1]
+func (x *PreviewLinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -109,61 +3128,165 @@ func (x *ShortenResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ShortenResponse.ProtoReflect.Descriptor instead.
-func (*ShortenResponse) Descriptor() ([]byte, []int) {
-	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use PreviewLinkRequest.ProtoReflect.Descriptor instead.
+func (*PreviewLinkRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{38}
 }
 
-func (x *ShortenResponse) GetShortUrl() string {
+func (x *PreviewLinkRequest) GetShortCode() string {
 	if x != nil {
-		return x.ShortUrl
+		return x.ShortCode
 	}
 	return ""
 }
 
-func (x *ShortenResponse) GetLongUrl() string {
+type LinkPreview struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	ShortCode         string                 `protobuf:"bytes,1,opt,name=short_code,proto3" json:"short_code,omitempty"`
+	LongUrl           string                 `protobuf:"bytes,2,opt,name=long_url,proto3" json:"long_url,omitempty"`      // Empty for password protected links and links with a click limit
+	Domain            string                 `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`          // Empty for password protected links and links with a click limit
+	CreatedAt         int64                  `protobuf:"varint,4,opt,name=created_at,proto3" json:"created_at,omitempty"` // Unix seconds
+	Clicks            int64                  `protobuf:"varint,5,opt,name=clicks,proto3" json:"clicks,omitempty"`         // Counted for as long as the owner's plan retains analytics
+	Safety            LinkSafety             `protobuf:"varint,6,opt,name=safety,proto3,enum=tinyurl.v1.LinkSafety" json:"safety,omitempty"`
+	Warning           string                 `protobuf:"bytes,7,opt,name=warning,proto3" json:"warning,omitempty"`
+	Broken            bool                   `protobuf:"varint,8,opt,name=broken,proto3" json:"broken,omitempty"`     // The destination failed its latest health checks
+	Targeted          bool                   `protobuf:"varint,9,opt,name=targeted,proto3" json:"targeted,omitempty"` // Some visitors are sent elsewhere by rules, schedules or variants
+	PasswordProtected bool                   `protobuf:"varint,10,opt,name=password_protected,proto3" json:"password_protected,omitempty"`
+	Limited           bool                   `protobuf:"varint,11,opt,name=limited,proto3" json:"limited,omitempty"` // The link has a click limit, long_url and domain are only shown by using a click
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *LinkPreview) Reset() {
+	*x = LinkPreview{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkPreview) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkPreview) ProtoMessage() {}
+
+func (x *LinkPreview) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkPreview.ProtoReflect.Descriptor instead.
+func (*LinkPreview) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *LinkPreview) GetShortCode() string {
+	if x != nil {
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *LinkPreview) GetLongUrl() string {
 	if x != nil {
 		return x.LongUrl
 	}
 	return ""
 }
 
-func (x *ShortenResponse) GetMessage() string {
+func (x *LinkPreview) GetDomain() string {
 	if x != nil {
-		return x.Message
+		return x.Domain
 	}
 	return ""
 }
 
-func (x *ShortenResponse) GetElapsedTime() string {
+func (x *LinkPreview) GetCreatedAt() int64 {
 	if x != nil {
-		return x.ElapsedTime
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *LinkPreview) GetClicks() int64 {
+	if x != nil {
+		return x.Clicks
+	}
+	return 0
+}
+
+func (x *LinkPreview) GetSafety() LinkSafety {
+	if x != nil {
+		return x.Safety
+	}
+	return LinkSafety_LINK_SAFETY_UNSPECIFIED
+}
+
+func (x *LinkPreview) GetWarning() string {
+	if x != nil {
+		return x.Warning
 	}
 	return ""
 }
 
-type GetOriginalRequest struct {
+func (x *LinkPreview) GetBroken() bool {
+	if x != nil {
+		return x.Broken
+	}
+	return false
+}
+
+func (x *LinkPreview) GetTargeted() bool {
+	if x != nil {
+		return x.Targeted
+	}
+	return false
+}
+
+func (x *LinkPreview) GetPasswordProtected() bool {
+	if x != nil {
+		return x.PasswordProtected
+	}
+	return false
+}
+
+func (x *LinkPreview) GetLimited() bool {
+	if x != nil {
+		return x.Limited
+	}
+	return false
+}
+
+type GetLinkStatsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,proto3" json:"short_code,omitempty"`
+	Days          int32                  `protobuf:"varint,2,opt,name=days,proto3" json:"days,omitempty"` // Optional, defaults to 30, capped by the plan's analytics retention
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetOriginalRequest) Reset() {
-	*x = GetOriginalRequest{}
-	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[2]
+func (x *GetLinkStatsRequest) Reset() {
+	*x = GetLinkStatsRequest{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetOriginalRequest) String() string {
+func (x *GetLinkStatsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetOriginalRequest) ProtoMessage() {}
+func (*GetLinkStatsRequest) ProtoMessage() {}
 
-func (x *GetOriginalRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[2]
+func (x *GetLinkStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -174,40 +3297,50 @@ func (x *GetOriginalRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetOriginalRequest.ProtoReflect.Descriptor instead.
-func (*GetOriginalRequest) Descriptor() ([]byte, []int) {
-	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use GetLinkStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetLinkStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{40}
 }
 
-func (x *GetOriginalRequest) GetShortCode() string {
+func (x *GetLinkStatsRequest) GetShortCode() string {
 	if x != nil {
 		return x.ShortCode
 	}
 	return ""
 }
 
-type GetOriginalResponse struct {
+func (x *GetLinkStatsRequest) GetDays() int32 {
+	if x != nil {
+		return x.Days
+	}
+	return 0
+}
+
+type LinkStats struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	LongUrl       string                 `protobuf:"bytes,1,opt,name=long_url,proto3" json:"long_url,omitempty"`
+	ShortCode     string                 `protobuf:"bytes,1,opt,name=short_code,proto3" json:"short_code,omitempty"`
+	Clicks        int64                  `protobuf:"varint,2,opt,name=clicks,proto3" json:"clicks,omitempty"`                                                                               // Total of the returned days
+	Counters      map[string]int64       `protobuf:"bytes,3,rep,name=counters,proto3" json:"counters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"` // Totals of the returned days, see DailyStats
+	Days          []*DailyStats          `protobuf:"bytes,4,rep,name=days,proto3" json:"days,omitempty"`                                                                                    // Most recent first, days without clicks are left out
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetOriginalResponse) Reset() {
-	*x = GetOriginalResponse{}
-	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[3]
+func (x *LinkStats) Reset() {
+	*x = LinkStats{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetOriginalResponse) String() string {
+func (x *LinkStats) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetOriginalResponse) ProtoMessage() {}
+func (*LinkStats) ProtoMessage() {}
 
-func (x *GetOriginalResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[3]
+func (x *LinkStats) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -218,43 +3351,495 @@ func (x *GetOriginalResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetOriginalResponse.ProtoReflect.Descriptor instead.
-func (*GetOriginalResponse) Descriptor() ([]byte, []int) {
-	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use LinkStats.ProtoReflect.Descriptor instead.
+func (*LinkStats) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{41}
 }
 
-func (x *GetOriginalResponse) GetLongUrl() string {
+func (x *LinkStats) GetShortCode() string {
 	if x != nil {
-		return x.LongUrl
+		return x.ShortCode
+	}
+	return ""
+}
+
+func (x *LinkStats) GetClicks() int64 {
+	if x != nil {
+		return x.Clicks
+	}
+	return 0
+}
+
+func (x *LinkStats) GetCounters() map[string]int64 {
+	if x != nil {
+		return x.Counters
+	}
+	return nil
+}
+
+func (x *LinkStats) GetDays() []*DailyStats {
+	if x != nil {
+		return x.Days
+	}
+	return nil
+}
+
+type DailyStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Date          string                 `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"` // YYYY-MM-DD in UTC
+	Clicks        int64                  `protobuf:"varint,2,opt,name=clicks,proto3" json:"clicks,omitempty"`
+	Counters      map[string]int64       `protobuf:"bytes,3,rep,name=counters,proto3" json:"counters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"` // "rule:<n>" counts clicks sent by the nth targeting rule, "variant:<name>" clicks sent to a variant, "schedule:<n>" clicks sent by the nth schedule
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DailyStats) Reset() {
+	*x = DailyStats{}
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DailyStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DailyStats) ProtoMessage() {}
+
+func (x *DailyStats) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tinyurl_v1_tinyurl_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DailyStats.ProtoReflect.Descriptor instead.
+func (*DailyStats) Descriptor() ([]byte, []int) {
+	return file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *DailyStats) GetDate() string {
+	if x != nil {
+		return x.Date
 	}
 	return ""
 }
 
+func (x *DailyStats) GetClicks() int64 {
+	if x != nil {
+		return x.Clicks
+	}
+	return 0
+}
+
+func (x *DailyStats) GetCounters() map[string]int64 {
+	if x != nil {
+		return x.Counters
+	}
+	return nil
+}
+
 var File_proto_tinyurl_v1_tinyurl_proto protoreflect.FileDescriptor
 
 const file_proto_tinyurl_v1_tinyurl_proto_rawDesc = "" +
 	"\n" +
 	"\x1eproto/tinyurl/v1/tinyurl.proto\x12\n" +
-	"tinyurl.v1\x1a\x1cgoogle/api/annotations.proto\"L\n" +
+	"tinyurl.v1\x1a\x1cgoogle/api/annotations.proto\x1a google/protobuf/field_mask.proto\"\xdc\a\n" +
 	"\x0eShortenRequest\x12\x1a\n" +
 	"\blong_url\x18\x01 \x01(\tR\blong_url\x12\x1e\n" +
 	"\n" +
 	"short_code\x18\x02 \x01(\tR\n" +
-	"short_code\"\x89\x01\n" +
+	"short_code\x12*\n" +
+	"\x10expires_in_hours\x18\x03 \x01(\x05R\x10expires_in_hours\x12$\n" +
+	"\rpow_challenge\x18\x04 \x01(\tR\rpow_challenge\x12\x1c\n" +
+	"\tpow_nonce\x18\x05 \x01(\tR\tpow_nonce\x12$\n" +
+	"\rfallback_urls\x18\x06 \x03(\tR\rfallback_urls\x12(\n" +
+	"\x0flast_resort_url\x18\a \x01(\tR\x0flast_resort_url\x12>\n" +
+	"\rredirect_type\x18\b \x01(\x0e2\x18.tinyurl.v1.RedirectTypeR\rredirect_type\x12*\n" +
+	"\x10path_passthrough\x18\t \x01(\bR\x10path_passthrough\x12J\n" +
+	"\x11query_passthrough\x18\n" +
+	" \x01(\x0e2\x1c.tinyurl.v1.QueryPassthroughR\x11query_passthrough\x12U\n" +
+	"\x0eparam_patterns\x18\v \x03(\v2-.tinyurl.v1.ShortenRequest.ParamPatternsEntryR\x0eparam_patterns\x12,\n" +
+	"\x05rules\x18\f \x03(\v2\x16.tinyurl.v1.TargetRuleR\x05rules\x12/\n" +
+	"\bvariants\x18\r \x03(\v2\x13.tinyurl.v1.VariantR\bvariants\x12\x1e\n" +
+	"\n" +
+	"not_before\x18\x0e \x01(\x03R\n" +
+	"not_before\x12\x1c\n" +
+	"\ttime_zone\x18\x0f \x01(\tR\ttime_zone\x122\n" +
+	"\tschedules\x18\x10 \x03(\v2\x14.tinyurl.v1.ScheduleR\tschedules\x12\x1a\n" +
+	"\bpassword\x18\x11 \x01(\tR\bpassword\x12\x1e\n" +
+	"\n" +
+	"max_clicks\x18\x12 \x01(\x05R\n" +
+	"max_clicks\x120\n" +
+	"\x06access\x18\x13 \x01(\v2\x18.tinyurl.v1.AccessPolicyR\x06access\x12\"\n" +
+	"\finterstitial\x18\x14 \x01(\bR\finterstitial\x12\x1a\n" +
+	"\btemplate\x18\x15 \x01(\bR\btemplate\x1a@\n" +
+	"\x12ParamPatternsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"r\n" +
+	"\fAccessPolicy\x12\x1c\n" +
+	"\tallow_ips\x18\x01 \x03(\tR\tallow_ips\x12\x1a\n" +
+	"\bdeny_ips\x18\x02 \x03(\tR\bdeny_ips\x12(\n" +
+	"\x0fallow_referrers\x18\x03 \x03(\tR\x0fallow_referrers\"\xa8\x01\n" +
+	"\bSchedule\x12\x12\n" +
+	"\x04days\x18\x01 \x03(\tR\x04days\x12\x1e\n" +
+	"\n" +
+	"start_time\x18\x02 \x01(\tR\n" +
+	"start_time\x12\x1a\n" +
+	"\bend_time\x18\x03 \x01(\tR\bend_time\x12\x1e\n" +
+	"\n" +
+	"start_date\x18\x04 \x01(\tR\n" +
+	"start_date\x12\x1a\n" +
+	"\bend_date\x18\x05 \x01(\tR\bend_date\x12\x10\n" +
+	"\x03url\x18\x06 \x01(\tR\x03url\"G\n" +
+	"\aVariant\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x16\n" +
+	"\x06weight\x18\x03 \x01(\x05R\x06weight\"\x96\x01\n" +
+	"\n" +
+	"TargetRule\x12\x0e\n" +
+	"\x02os\x18\x01 \x03(\tR\x02os\x12\x16\n" +
+	"\x06device\x18\x02 \x03(\tR\x06device\x12\x18\n" +
+	"\abrowser\x18\x03 \x03(\tR\abrowser\x12\x10\n" +
+	"\x03url\x18\x04 \x01(\tR\x03url\x12\x18\n" +
+	"\acountry\x18\x05 \x03(\tR\acountry\x12\x1a\n" +
+	"\blanguage\x18\x06 \x03(\tR\blanguage\"\x9d\x01\n" +
 	"\x0fShortenResponse\x12\x1c\n" +
 	"\tshort_url\x18\x01 \x01(\tR\tshort_url\x12\x1a\n" +
 	"\blong_url\x18\x02 \x01(\tR\blong_url\x12\x18\n" +
 	"\amessage\x18\x03 \x01(\tR\amessage\x12\"\n" +
-	"\felapsed_time\x18\x04 \x01(\tR\felapsed_time\"4\n" +
+	"\felapsed_time\x18\x04 \x01(\tR\felapsed_time\x12\x12\n" +
+	"\x04plan\x18\x05 \x01(\tR\x04plan\"\x90\x02\n" +
 	"\x12GetOriginalRequest\x12\x1e\n" +
 	"\n" +
 	"short_code\x18\x01 \x01(\tR\n" +
-	"short_code\"1\n" +
+	"short_code\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x14\n" +
+	"\x05query\x18\x03 \x01(\tR\x05query\x12\x1e\n" +
+	"\n" +
+	"user_agent\x18\x04 \x01(\tR\n" +
+	"user_agent\x12(\n" +
+	"\x0faccept_language\x18\x05 \x01(\tR\x0faccept_language\x12\x18\n" +
+	"\avariant\x18\x06 \x01(\tR\avariant\x12\"\n" +
+	"\funlock_token\x18\a \x01(\tR\funlock_token\x12\x18\n" +
+	"\apreview\x18\b \x01(\bR\apreviewJ\x04\b\t\x10\n" +
+	"R\breferrer\"\x87\x03\n" +
 	"\x13GetOriginalResponse\x12\x1a\n" +
-	"\blong_url\x18\x01 \x01(\tR\blong_url2\xd0\x01\n" +
+	"\blong_url\x18\x01 \x01(\tR\blong_url\x12\x18\n" +
+	"\aflagged\x18\x02 \x01(\bR\aflagged\x12\x18\n" +
+	"\awarning\x18\x03 \x01(\tR\awarning\x12\x1a\n" +
+	"\bfallback\x18\x04 \x01(\bR\bfallback\x12\x12\n" +
+	"\x04rule\x18\a \x01(\x05R\x04rule\x12\x1a\n" +
+	"\btargeted\x18\b \x01(\bR\btargeted\x12\x18\n" +
+	"\avariant\x18\t \x01(\tR\avariant\x12\x1c\n" +
+	"\tprotected\x18\n" +
+	" \x01(\bR\tprotected\x12\x18\n" +
+	"\alimited\x18\v \x01(\bR\alimited\x12\"\n" +
+	"\finterstitial\x18\f \x01(\bR\finterstitial\x12>\n" +
+	"\rredirect_type\x18\x05 \x01(\x0e2\x18.tinyurl.v1.RedirectTypeR\rredirect_type\x12\x1e\n" +
+	"\n" +
+	"expires_at\x18\x06 \x01(\x03R\n" +
+	"expires_at\"\x15\n" +
+	"\x13GetChallengeRequest\"\x90\x01\n" +
+	"\x14GetChallengeResponse\x12\x1a\n" +
+	"\brequired\x18\x01 \x01(\bR\brequired\x12\x1c\n" +
+	"\tchallenge\x18\x02 \x01(\tR\tchallenge\x12\x1e\n" +
+	"\n" +
+	"difficulty\x18\x03 \x01(\x05R\n" +
+	"difficulty\x12\x1e\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\x03R\n" +
+	"expires_at\"\x17\n" +
+	"\x15ReloadPoliciesRequest\"\x18\n" +
+	"\x16GetPolicyStatusRequest\"\xcc\x01\n" +
+	"\fPolicyStatus\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x1c\n" +
+	"\tloaded_at\x18\x02 \x01(\x03R\tloaded_at\x12 \n" +
+	"\vallow_rules\x18\x03 \x01(\x05R\vallow_rules\x12 \n" +
+	"\vblock_rules\x18\x04 \x01(\x05R\vblock_rules\x12&\n" +
+	"\x0edefault_action\x18\x05 \x01(\tR\x0edefault_action\x12\x1e\n" +
+	"\n" +
+	"last_error\x18\x06 \x01(\tR\n" +
+	"last_error\"O\n" +
+	"\x17ImportThreatListRequest\x12\x1a\n" +
+	"\bprefixes\x18\x01 \x03(\tR\bprefixes\x12\x18\n" +
+	"\areplace\x18\x02 \x01(\bR\areplace\"0\n" +
+	"\x18ImportThreatListResponse\x12\x14\n" +
+	"\x05total\x18\x01 \x01(\x05R\x05total\"\x7f\n" +
+	"\x11ReportLinkRequest\x12\x1e\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\n" +
+	"short_code\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x18\n" +
+	"\adetails\x18\x03 \x01(\tR\adetails\x12\x18\n" +
+	"\acontact\x18\x04 \x01(\tR\acontact\">\n" +
+	"\x12ReportLinkResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xae\x02\n" +
+	"\x06Report\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1e\n" +
+	"\n" +
+	"short_code\x18\x02 \x01(\tR\n" +
+	"short_code\x12\x1a\n" +
+	"\blong_url\x18\x03 \x01(\tR\blong_url\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12\x18\n" +
+	"\adetails\x18\x05 \x01(\tR\adetails\x12\x18\n" +
+	"\acontact\x18\x06 \x01(\tR\acontact\x12\x1e\n" +
+	"\n" +
+	"created_at\x18\a \x01(\x03R\n" +
+	"created_at\x12\x16\n" +
+	"\x06status\x18\b \x01(\tR\x06status\x12\x1e\n" +
+	"\n" +
+	"resolution\x18\t \x01(\tR\n" +
+	"resolution\x12\x12\n" +
+	"\x04note\x18\n" +
+	" \x01(\tR\x04note\x12 \n" +
+	"\vresolved_at\x18\v \x01(\x03R\vresolved_at\"Z\n" +
+	"\x12ListReportsRequest\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\"Y\n" +
+	"\x13ListReportsResponse\x12,\n" +
+	"\areports\x18\x01 \x03(\v2\x12.tinyurl.v1.ReportR\areports\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\"\x82\x01\n" +
+	"\x14ResolveReportRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x120\n" +
+	"\x06action\x18\x02 \x01(\x0e2\x18.tinyurl.v1.ReportActionR\x06action\x12\x12\n" +
+	"\x04note\x18\x03 \x01(\tR\x04note\x12\x14\n" +
+	"\x05legal\x18\x04 \x01(\bR\x05legal\"b\n" +
+	"\x12DisableLinkRequest\x12\x1e\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\n" +
+	"short_code\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x14\n" +
+	"\x05legal\x18\x03 \x01(\bR\x05legal\"\x15\n" +
+	"\x13DisableLinkResponse\"3\n" +
+	"\x11DeleteLinkRequest\x12\x1e\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\n" +
+	"short_code\"\x14\n" +
+	"\x12DeleteLinkResponse\"\xf0\x01\n" +
+	"\x16BulkUpdateLinksRequest\x12\x1a\n" +
+	"\bpatterns\x18\x01 \x03(\tR\bpatterns\x12.\n" +
+	"\x06action\x18\x02 \x01(\x0e2\x16.tinyurl.v1.BulkActionR\x06action\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x14\n" +
+	"\x05legal\x18\x04 \x01(\bR\x05legal\x12\"\n" +
+	"\fnew_long_url\x18\x05 \x01(\tR\fnew_long_url\x12\x18\n" +
+	"\adry_run\x18\x06 \x01(\bR\adry_run\x12\x1e\n" +
+	"\n" +
+	"batch_size\x18\a \x01(\x05R\n" +
+	"batch_size\"G\n" +
+	"\tBulkMatch\x12\x1e\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\n" +
+	"short_code\x12\x1a\n" +
+	"\blong_url\x18\x02 \x01(\tR\blong_url\"\xa7\x01\n" +
+	"\x12BulkUpdateProgress\x12\x18\n" +
+	"\ascanned\x18\x01 \x01(\x03R\ascanned\x12\x18\n" +
+	"\amatched\x18\x02 \x01(\x03R\amatched\x12\x18\n" +
+	"\aupdated\x18\x03 \x01(\x03R\aupdated\x12/\n" +
+	"\amatches\x18\x04 \x03(\v2\x15.tinyurl.v1.BulkMatchR\amatches\x12\x12\n" +
+	"\x04done\x18\x05 \x01(\bR\x04done\"0\n" +
+	"\x0eGetLinkRequest\x12\x1e\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\n" +
+	"short_code\"\xa6\x01\n" +
+	"\n" +
+	"LinkHealth\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x12\x1e\n" +
+	"\n" +
+	"checked_at\x18\x03 \x01(\x03R\n" +
+	"checked_at\x122\n" +
+	"\x14consecutive_failures\x18\x04 \x01(\x05R\x14consecutive_failures\x12\x16\n" +
+	"\x06broken\x18\x05 \x01(\bR\x06broken\"\x9e\t\n" +
+	"\bLinkInfo\x12\x1e\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\n" +
+	"short_code\x12\x1a\n" +
+	"\blong_url\x18\x02 \x01(\tR\blong_url\x12\x1e\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\x03R\n" +
+	"created_at\x12\x1e\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\x03R\n" +
+	"expires_at\x12\x14\n" +
+	"\x05owner\x18\x05 \x01(\tR\x05owner\x12\x12\n" +
+	"\x04plan\x18\x06 \x01(\tR\x04plan\x12\x18\n" +
+	"\aflagged\x18\a \x01(\bR\aflagged\x12\x1a\n" +
+	"\bdisabled\x18\b \x01(\bR\bdisabled\x12(\n" +
+	"\x0fdisabled_reason\x18\t \x01(\tR\x0fdisabled_reason\x12.\n" +
+	"\x06health\x18\n" +
+	" \x01(\v2\x16.tinyurl.v1.LinkHealthR\x06health\x122\n" +
+	"\tfallbacks\x18\v \x03(\v2\x14.tinyurl.v1.FallbackR\tfallbacks\x12(\n" +
+	"\x0flast_resort_url\x18\f \x01(\tR\x0flast_resort_url\x12>\n" +
+	"\rredirect_type\x18\r \x01(\x0e2\x18.tinyurl.v1.RedirectTypeR\rredirect_type\x12*\n" +
+	"\x10path_passthrough\x18\x0e \x01(\bR\x10path_passthrough\x12J\n" +
+	"\x11query_passthrough\x18\x0f \x01(\x0e2\x1c.tinyurl.v1.QueryPassthroughR\x11query_passthrough\x12O\n" +
+	"\x0eparam_patterns\x18\x10 \x03(\v2'.tinyurl.v1.LinkInfo.ParamPatternsEntryR\x0eparam_patterns\x12,\n" +
+	"\x05rules\x18\x11 \x03(\v2\x16.tinyurl.v1.TargetRuleR\x05rules\x12/\n" +
+	"\bvariants\x18\x12 \x03(\v2\x13.tinyurl.v1.VariantR\bvariants\x12\x1e\n" +
+	"\n" +
+	"not_before\x18\x13 \x01(\x03R\n" +
+	"not_before\x12\x1c\n" +
+	"\ttime_zone\x18\x14 \x01(\tR\ttime_zone\x122\n" +
+	"\tschedules\x18\x15 \x03(\v2\x14.tinyurl.v1.ScheduleR\tschedules\x12.\n" +
+	"\x12password_protected\x18\x16 \x01(\bR\x12password_protected\x12\x1e\n" +
+	"\n" +
+	"max_clicks\x18\x17 \x01(\x05R\n" +
+	"max_clicks\x12 \n" +
+	"\vclicks_left\x18\x18 \x01(\x03R\vclicks_left\x120\n" +
+	"\x06access\x18\x19 \x01(\v2\x18.tinyurl.v1.AccessPolicyR\x06access\x12\"\n" +
+	"\finterstitial\x18\x1a \x01(\bR\finterstitial\x12\x1a\n" +
+	"\btemplate\x18\x1b \x01(\bR\btemplate\x1a@\n" +
+	"\x12ParamPatternsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"L\n" +
+	"\bFallback\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12.\n" +
+	"\x06health\x18\x02 \x01(\v2\x16.tinyurl.v1.LinkHealthR\x06health\"\xf0\x05\n" +
+	"\fLinkSettings\x12$\n" +
+	"\rfallback_urls\x18\x01 \x03(\tR\rfallback_urls\x12(\n" +
+	"\x0flast_resort_url\x18\x02 \x01(\tR\x0flast_resort_url\x12>\n" +
+	"\rredirect_type\x18\x03 \x01(\x0e2\x18.tinyurl.v1.RedirectTypeR\rredirect_type\x12*\n" +
+	"\x10path_passthrough\x18\x04 \x01(\bR\x10path_passthrough\x12J\n" +
+	"\x11query_passthrough\x18\x05 \x01(\x0e2\x1c.tinyurl.v1.QueryPassthroughR\x11query_passthrough\x12S\n" +
+	"\x0eparam_patterns\x18\x06 \x03(\v2+.tinyurl.v1.LinkSettings.ParamPatternsEntryR\x0eparam_patterns\x12,\n" +
+	"\x05rules\x18\a \x03(\v2\x16.tinyurl.v1.TargetRuleR\x05rules\x12/\n" +
+	"\bvariants\x18\b \x03(\v2\x13.tinyurl.v1.VariantR\bvariants\x12\x1e\n" +
+	"\n" +
+	"not_before\x18\t \x01(\x03R\n" +
+	"not_before\x12\x1c\n" +
+	"\ttime_zone\x18\n" +
+	" \x01(\tR\ttime_zone\x122\n" +
+	"\tschedules\x18\v \x03(\v2\x14.tinyurl.v1.ScheduleR\tschedules\x12\x1a\n" +
+	"\bpassword\x18\f \x01(\tR\bpassword\x120\n" +
+	"\x06access\x18\r \x01(\v2\x18.tinyurl.v1.AccessPolicyR\x06access\x12\"\n" +
+	"\finterstitial\x18\x0e \x01(\bR\finterstitial\x1a@\n" +
+	"\x12ParamPatternsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa7\x01\n" +
+	"\x11UpdateLinkRequest\x12\x1e\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\n" +
+	"short_code\x124\n" +
+	"\bsettings\x18\x02 \x01(\v2\x18.tinyurl.v1.LinkSettingsR\bsettings\x12<\n" +
+	"\vupdate_mask\x18\x03 \x01(\v2\x1a.google.protobuf.FieldMaskR\vupdate_mask\"\x18\n" +
+	"\x16ListBrokenLinksRequest\"E\n" +
+	"\x17ListBrokenLinksResponse\x12*\n" +
+	"\x05links\x18\x01 \x03(\v2\x14.tinyurl.v1.LinkInfoR\x05links\"_\n" +
+	"\x11UnlockLinkRequest\x12\x1e\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\n" +
+	"short_code\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpasswordJ\x04\b\x03\x10\x04R\breferrer\"J\n" +
+	"\x12UnlockLinkResponse\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x1e\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\x03R\n" +
+	"expires_at\"D\n" +
+	"\x12PreviewLinkRequest\x12\x1e\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\n" +
+	"short_codeJ\x04\b\x02\x10\x03R\breferrer\"\xe1\x02\n" +
+	"\vLinkPreview\x12\x1e\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\n" +
+	"short_code\x12\x1a\n" +
+	"\blong_url\x18\x02 \x01(\tR\blong_url\x12\x16\n" +
+	"\x06domain\x18\x03 \x01(\tR\x06domain\x12\x1e\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\x03R\n" +
+	"created_at\x12\x16\n" +
+	"\x06clicks\x18\x05 \x01(\x03R\x06clicks\x12.\n" +
+	"\x06safety\x18\x06 \x01(\x0e2\x16.tinyurl.v1.LinkSafetyR\x06safety\x12\x18\n" +
+	"\awarning\x18\a \x01(\tR\awarning\x12\x16\n" +
+	"\x06broken\x18\b \x01(\bR\x06broken\x12\x1a\n" +
+	"\btargeted\x18\t \x01(\bR\btargeted\x12.\n" +
+	"\x12password_protected\x18\n" +
+	" \x01(\bR\x12password_protected\x12\x18\n" +
+	"\alimited\x18\v \x01(\bR\alimited\"I\n" +
+	"\x13GetLinkStatsRequest\x12\x1e\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\n" +
+	"short_code\x12\x12\n" +
+	"\x04days\x18\x02 \x01(\x05R\x04days\"\xed\x01\n" +
+	"\tLinkStats\x12\x1e\n" +
+	"\n" +
+	"short_code\x18\x01 \x01(\tR\n" +
+	"short_code\x12\x16\n" +
+	"\x06clicks\x18\x02 \x01(\x03R\x06clicks\x12?\n" +
+	"\bcounters\x18\x03 \x03(\v2#.tinyurl.v1.LinkStats.CountersEntryR\bcounters\x12*\n" +
+	"\x04days\x18\x04 \x03(\v2\x16.tinyurl.v1.DailyStatsR\x04days\x1a;\n" +
+	"\rCountersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"\xb7\x01\n" +
+	"\n" +
+	"DailyStats\x12\x12\n" +
+	"\x04date\x18\x01 \x01(\tR\x04date\x12\x16\n" +
+	"\x06clicks\x18\x02 \x01(\x03R\x06clicks\x12@\n" +
+	"\bcounters\x18\x03 \x03(\v2$.tinyurl.v1.DailyStats.CountersEntryR\bcounters\x1a;\n" +
+	"\rCountersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01*w\n" +
+	"\x10QueryPassthrough\x12\x1e\n" +
+	"\x1aQUERY_PASSTHROUGH_DISABLED\x10\x00\x12\"\n" +
+	"\x1eQUERY_PASSTHROUGH_REQUEST_WINS\x10\x01\x12\x1f\n" +
+	"\x1bQUERY_PASSTHROUGH_LINK_WINS\x10\x02*\xde\x01\n" +
+	"\fRedirectType\x12\x1d\n" +
+	"\x19REDIRECT_TYPE_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11REDIRECT_TYPE_301\x10\x01\x12\x15\n" +
+	"\x11REDIRECT_TYPE_302\x10\x02\x12\x15\n" +
+	"\x11REDIRECT_TYPE_303\x10\x03\x12\x15\n" +
+	"\x11REDIRECT_TYPE_307\x10\x04\x12\x15\n" +
+	"\x11REDIRECT_TYPE_308\x10\x05\x12\x1e\n" +
+	"\x1aREDIRECT_TYPE_META_REFRESH\x10\x06\x12\x1c\n" +
+	"\x18REDIRECT_TYPE_JAVASCRIPT\x10\a*}\n" +
+	"\fReportAction\x12\x1d\n" +
+	"\x19REPORT_ACTION_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15REPORT_ACTION_DISMISS\x10\x01\x12\x19\n" +
+	"\x15REPORT_ACTION_DISABLE\x10\x02\x12\x18\n" +
+	"\x14REPORT_ACTION_DELETE\x10\x03*s\n" +
+	"\n" +
+	"BulkAction\x12\x1b\n" +
+	"\x17BULK_ACTION_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13BULK_ACTION_DISABLE\x10\x01\x12\x16\n" +
+	"\x12BULK_ACTION_DELETE\x10\x02\x12\x17\n" +
+	"\x13BULK_ACTION_REPOINT\x10\x03*o\n" +
+	"\n" +
+	"LinkSafety\x12\x1b\n" +
+	"\x17LINK_SAFETY_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eLINK_SAFETY_OK\x10\x01\x12\x17\n" +
+	"\x13LINK_SAFETY_FLAGGED\x10\x02\x12\x17\n" +
+	"\x13LINK_SAFETY_BLOCKED\x10\x032\xe6\x0f\n" +
 	"\aTinyURL\x12W\n" +
 	"\aShorten\x12\x1a.tinyurl.v1.ShortenRequest\x1a\x1b.tinyurl.v1.ShortenResponse\"\x13\x82\xd3\xe4\x93\x02\r:\x01*\"\b/tinyurl\x12l\n" +
-	"\vGetOriginal\x12\x1e.tinyurl.v1.GetOriginalRequest\x1a\x1f.tinyurl.v1.GetOriginalResponse\"\x1c\x82\xd3\xe4\x93\x02\x16\x12\x14/v1/url/{short_code}BEZCgithub.com/eldivategar/simple-tinyurl-go/proto/tinyurl/v1;tinyurlv1b\x06proto3"
+	"\vGetOriginal\x12\x1e.tinyurl.v1.GetOriginalRequest\x1a\x1f.tinyurl.v1.GetOriginalResponse\"\x1c\x82\xd3\xe4\x93\x02\x16\x12\x14/v1/url/{short_code}\x12h\n" +
+	"\fGetChallenge\x12\x1f.tinyurl.v1.GetChallengeRequest\x1a .tinyurl.v1.GetChallengeResponse\"\x15\x82\xd3\xe4\x93\x02\x0f\x12\r/v1/challenge\x12s\n" +
+	"\x0eReloadPolicies\x12!.tinyurl.v1.ReloadPoliciesRequest\x1a\x18.tinyurl.v1.PolicyStatus\"$\x82\xd3\xe4\x93\x02\x1e:\x01*\"\x19/v1/admin/policies:reload\x12k\n" +
+	"\x0fGetPolicyStatus\x12\".tinyurl.v1.GetPolicyStatusRequest\x1a\x18.tinyurl.v1.PolicyStatus\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/v1/admin/policies\x12\x82\x01\n" +
+	"\x10ImportThreatList\x12#.tinyurl.v1.ImportThreatListRequest\x1a$.tinyurl.v1.ImportThreatListResponse\"#\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/v1/admin/threats:import\x12c\n" +
+	"\n" +
+	"ReportLink\x12\x1d.tinyurl.v1.ReportLinkRequest\x1a\x1e.tinyurl.v1.ReportLinkResponse\"\x16\x82\xd3\xe4\x93\x02\x10:\x01*\"\v/v1/reports\x12i\n" +
+	"\vListReports\x12\x1e.tinyurl.v1.ListReportsRequest\x1a\x1f.tinyurl.v1.ListReportsResponse\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/admin/reports\x12p\n" +
+	"\rResolveReport\x12 .tinyurl.v1.ResolveReportRequest\x1a\x12.tinyurl.v1.Report\")\x82\xd3\xe4\x93\x02#:\x01*\"\x1e/v1/admin/reports/{id}:resolve\x12\x7f\n" +
+	"\vDisableLink\x12\x1e.tinyurl.v1.DisableLinkRequest\x1a\x1f.tinyurl.v1.DisableLinkResponse\"/\x82\xd3\xe4\x93\x02):\x01*\"$/v1/admin/links/{short_code}:disable\x12q\n" +
+	"\n" +
+	"DeleteLink\x12\x1d.tinyurl.v1.DeleteLinkRequest\x1a\x1e.tinyurl.v1.DeleteLinkResponse\"$\x82\xd3\xe4\x93\x02\x1e*\x1c/v1/admin/links/{short_code}\x12x\n" +
+	"\x0fBulkUpdateLinks\x12\".tinyurl.v1.BulkUpdateLinksRequest\x1a\x1e.tinyurl.v1.BulkUpdateProgress\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/v1/admin/links:bulk0\x01\x12[\n" +
+	"\aGetLink\x12\x1a.tinyurl.v1.GetLinkRequest\x1a\x14.tinyurl.v1.LinkInfo\"\x1e\x82\xd3\xe4\x93\x02\x18\x12\x16/v1/links/{short_code}\x12k\n" +
+	"\n" +
+	"UpdateLink\x12\x1d.tinyurl.v1.UpdateLinkRequest\x1a\x14.tinyurl.v1.LinkInfo\"(\x82\xd3\xe4\x93\x02\":\bsettings2\x16/v1/links/{short_code}\x12t\n" +
+	"\x0fListBrokenLinks\x12\".tinyurl.v1.ListBrokenLinksRequest\x1a#.tinyurl.v1.ListBrokenLinksResponse\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/v1/links:broken\x12l\n" +
+	"\fGetLinkStats\x12\x1f.tinyurl.v1.GetLinkStatsRequest\x1a\x15.tinyurl.v1.LinkStats\"$\x82\xd3\xe4\x93\x02\x1e\x12\x1c/v1/links/{short_code}/stats\x12u\n" +
+	"\n" +
+	"UnlockLink\x12\x1d.tinyurl.v1.UnlockLinkRequest\x1a\x1e.tinyurl.v1.UnlockLinkResponse\"(\x82\xd3\xe4\x93\x02\":\x01*\"\x1d/v1/links/{short_code}:unlock\x12n\n" +
+	"\vPreviewLink\x12\x1e.tinyurl.v1.PreviewLinkRequest\x1a\x17.tinyurl.v1.LinkPreview\"&\x82\xd3\xe4\x93\x02 \x12\x1e/v1/links/{short_code}/previewBEZCgithub.com/eldivategar/simple-tinyurl-go/proto/tinyurl/v1;tinyurlv1b\x06proto3"
 
 var (
 	file_proto_tinyurl_v1_tinyurl_proto_rawDescOnce sync.Once
@@ -268,23 +3853,142 @@ func file_proto_tinyurl_v1_tinyurl_proto_rawDescGZIP() []byte {
 	return file_proto_tinyurl_v1_tinyurl_proto_rawDescData
 }
 
-var file_proto_tinyurl_v1_tinyurl_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_proto_tinyurl_v1_tinyurl_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_proto_tinyurl_v1_tinyurl_proto_msgTypes = make([]protoimpl.MessageInfo, 48)
 var file_proto_tinyurl_v1_tinyurl_proto_goTypes = []any{
-	(*ShortenRequest)(nil),      // 0: tinyurl.v1.ShortenRequest
-	(*ShortenResponse)(nil),     // 1: tinyurl.v1.ShortenResponse
-	(*GetOriginalRequest)(nil),  // 2: tinyurl.v1.GetOriginalRequest
-	(*GetOriginalResponse)(nil), // 3: tinyurl.v1.GetOriginalResponse
+	(QueryPassthrough)(0),            // 0: tinyurl.v1.QueryPassthrough
+	(RedirectType)(0),                // 1: tinyurl.v1.RedirectType
+	(ReportAction)(0),                // 2: tinyurl.v1.ReportAction
+	(BulkAction)(0),                  // 3: tinyurl.v1.BulkAction
+	(LinkSafety)(0),                  // 4: tinyurl.v1.LinkSafety
+	(*ShortenRequest)(nil),           // 5: tinyurl.v1.ShortenRequest
+	(*AccessPolicy)(nil),             // 6: tinyurl.v1.AccessPolicy
+	(*Schedule)(nil),                 // 7: tinyurl.v1.Schedule
+	(*Variant)(nil),                  // 8: tinyurl.v1.Variant
+	(*TargetRule)(nil),               // 9: tinyurl.v1.TargetRule
+	(*ShortenResponse)(nil),          // 10: tinyurl.v1.ShortenResponse
+	(*GetOriginalRequest)(nil),       // 11: tinyurl.v1.GetOriginalRequest
+	(*GetOriginalResponse)(nil),      // 12: tinyurl.v1.GetOriginalResponse
+	(*GetChallengeRequest)(nil),      // 13: tinyurl.v1.GetChallengeRequest
+	(*GetChallengeResponse)(nil),     // 14: tinyurl.v1.GetChallengeResponse
+	(*ReloadPoliciesRequest)(nil),    // 15: tinyurl.v1.ReloadPoliciesRequest
+	(*GetPolicyStatusRequest)(nil),   // 16: tinyurl.v1.GetPolicyStatusRequest
+	(*PolicyStatus)(nil),             // 17: tinyurl.v1.PolicyStatus
+	(*ImportThreatListRequest)(nil),  // 18: tinyurl.v1.ImportThreatListRequest
+	(*ImportThreatListResponse)(nil), // 19: tinyurl.v1.ImportThreatListResponse
+	(*ReportLinkRequest)(nil),        // 20: tinyurl.v1.ReportLinkRequest
+	(*ReportLinkResponse)(nil),       // 21: tinyurl.v1.ReportLinkResponse
+	(*Report)(nil),                   // 22: tinyurl.v1.Report
+	(*ListReportsRequest)(nil),       // 23: tinyurl.v1.ListReportsRequest
+	(*ListReportsResponse)(nil),      // 24: tinyurl.v1.ListReportsResponse
+	(*ResolveReportRequest)(nil),     // 25: tinyurl.v1.ResolveReportRequest
+	(*DisableLinkRequest)(nil),       // 26: tinyurl.v1.DisableLinkRequest
+	(*DisableLinkResponse)(nil),      // 27: tinyurl.v1.DisableLinkResponse
+	(*DeleteLinkRequest)(nil),        // 28: tinyurl.v1.DeleteLinkRequest
+	(*DeleteLinkResponse)(nil),       // 29: tinyurl.v1.DeleteLinkResponse
+	(*BulkUpdateLinksRequest)(nil),   // 30: tinyurl.v1.BulkUpdateLinksRequest
+	(*BulkMatch)(nil),                // 31: tinyurl.v1.BulkMatch
+	(*BulkUpdateProgress)(nil),       // 32: tinyurl.v1.BulkUpdateProgress
+	(*GetLinkRequest)(nil),           // 33: tinyurl.v1.GetLinkRequest
+	(*LinkHealth)(nil),               // 34: tinyurl.v1.LinkHealth
+	(*LinkInfo)(nil),                 // 35: tinyurl.v1.LinkInfo
+	(*Fallback)(nil),                 // 36: tinyurl.v1.Fallback
+	(*LinkSettings)(nil),             // 37: tinyurl.v1.LinkSettings
+	(*UpdateLinkRequest)(nil),        // 38: tinyurl.v1.UpdateLinkRequest
+	(*ListBrokenLinksRequest)(nil),   // 39: tinyurl.v1.ListBrokenLinksRequest
+	(*ListBrokenLinksResponse)(nil),  // 40: tinyurl.v1.ListBrokenLinksResponse
+	(*UnlockLinkRequest)(nil),        // 41: tinyurl.v1.UnlockLinkRequest
+	(*UnlockLinkResponse)(nil),       // 42: tinyurl.v1.UnlockLinkResponse
+	(*PreviewLinkRequest)(nil),       // 43: tinyurl.v1.PreviewLinkRequest
+	(*LinkPreview)(nil),              // 44: tinyurl.v1.LinkPreview
+	(*GetLinkStatsRequest)(nil),      // 45: tinyurl.v1.GetLinkStatsRequest
+	(*LinkStats)(nil),                // 46: tinyurl.v1.LinkStats
+	(*DailyStats)(nil),               // 47: tinyurl.v1.DailyStats
+	nil,                              // 48: tinyurl.v1.ShortenRequest.ParamPatternsEntry
+	nil,                              // 49: tinyurl.v1.LinkInfo.ParamPatternsEntry
+	nil,                              // 50: tinyurl.v1.LinkSettings.ParamPatternsEntry
+	nil,                              // 51: tinyurl.v1.LinkStats.CountersEntry
+	nil,                              // 52: tinyurl.v1.DailyStats.CountersEntry
+	(*fieldmaskpb.FieldMask)(nil),    // 53: google.protobuf.FieldMask
 }
 var file_proto_tinyurl_v1_tinyurl_proto_depIdxs = []int32{
-	0, // 0: tinyurl.v1.TinyURL.Shorten:input_type -> tinyurl.v1.ShortenRequest
-	2, // 1: tinyurl.v1.TinyURL.GetOriginal:input_type -> tinyurl.v1.GetOriginalRequest
-	1, // 2: tinyurl.v1.TinyURL.Shorten:output_type -> tinyurl.v1.ShortenResponse
-	3, // 3: tinyurl.v1.TinyURL.GetOriginal:output_type -> tinyurl.v1.GetOriginalResponse
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	1,  // 0: tinyurl.v1.ShortenRequest.redirect_type:type_name -> tinyurl.v1.RedirectType
+	0,  // 1: tinyurl.v1.ShortenRequest.query_passthrough:type_name -> tinyurl.v1.QueryPassthrough
+	48, // 2: tinyurl.v1.ShortenRequest.param_patterns:type_name -> tinyurl.v1.ShortenRequest.ParamPatternsEntry
+	9,  // 3: tinyurl.v1.ShortenRequest.rules:type_name -> tinyurl.v1.TargetRule
+	8,  // 4: tinyurl.v1.ShortenRequest.variants:type_name -> tinyurl.v1.Variant
+	7,  // 5: tinyurl.v1.ShortenRequest.schedules:type_name -> tinyurl.v1.Schedule
+	6,  // 6: tinyurl.v1.ShortenRequest.access:type_name -> tinyurl.v1.AccessPolicy
+	1,  // 7: tinyurl.v1.GetOriginalResponse.redirect_type:type_name -> tinyurl.v1.RedirectType
+	22, // 8: tinyurl.v1.ListReportsResponse.reports:type_name -> tinyurl.v1.Report
+	2,  // 9: tinyurl.v1.ResolveReportRequest.action:type_name -> tinyurl.v1.ReportAction
+	3,  // 10: tinyurl.v1.BulkUpdateLinksRequest.action:type_name -> tinyurl.v1.BulkAction
+	31, // 11: tinyurl.v1.BulkUpdateProgress.matches:type_name -> tinyurl.v1.BulkMatch
+	34, // 12: tinyurl.v1.LinkInfo.health:type_name -> tinyurl.v1.LinkHealth
+	36, // 13: tinyurl.v1.LinkInfo.fallbacks:type_name -> tinyurl.v1.Fallback
+	1,  // 14: tinyurl.v1.LinkInfo.redirect_type:type_name -> tinyurl.v1.RedirectType
+	0,  // 15: tinyurl.v1.LinkInfo.query_passthrough:type_name -> tinyurl.v1.QueryPassthrough
+	49, // 16: tinyurl.v1.LinkInfo.param_patterns:type_name -> tinyurl.v1.LinkInfo.ParamPatternsEntry
+	9,  // 17: tinyurl.v1.LinkInfo.rules:type_name -> tinyurl.v1.TargetRule
+	8,  // 18: tinyurl.v1.LinkInfo.variants:type_name -> tinyurl.v1.Variant
+	7,  // 19: tinyurl.v1.LinkInfo.schedules:type_name -> tinyurl.v1.Schedule
+	6,  // 20: tinyurl.v1.LinkInfo.access:type_name -> tinyurl.v1.AccessPolicy
+	34, // 21: tinyurl.v1.Fallback.health:type_name -> tinyurl.v1.LinkHealth
+	1,  // 22: tinyurl.v1.LinkSettings.redirect_type:type_name -> tinyurl.v1.RedirectType
+	0,  // 23: tinyurl.v1.LinkSettings.query_passthrough:type_name -> tinyurl.v1.QueryPassthrough
+	50, // 24: tinyurl.v1.LinkSettings.param_patterns:type_name -> tinyurl.v1.LinkSettings.ParamPatternsEntry
+	9,  // 25: tinyurl.v1.LinkSettings.rules:type_name -> tinyurl.v1.TargetRule
+	8,  // 26: tinyurl.v1.LinkSettings.variants:type_name -> tinyurl.v1.Variant
+	7,  // 27: tinyurl.v1.LinkSettings.schedules:type_name -> tinyurl.v1.Schedule
+	6,  // 28: tinyurl.v1.LinkSettings.access:type_name -> tinyurl.v1.AccessPolicy
+	37, // 29: tinyurl.v1.UpdateLinkRequest.settings:type_name -> tinyurl.v1.LinkSettings
+	53, // 30: tinyurl.v1.UpdateLinkRequest.update_mask:type_name -> google.protobuf.FieldMask
+	35, // 31: tinyurl.v1.ListBrokenLinksResponse.links:type_name -> tinyurl.v1.LinkInfo
+	4,  // 32: tinyurl.v1.LinkPreview.safety:type_name -> tinyurl.v1.LinkSafety
+	51, // 33: tinyurl.v1.LinkStats.counters:type_name -> tinyurl.v1.LinkStats.CountersEntry
+	47, // 34: tinyurl.v1.LinkStats.days:type_name -> tinyurl.v1.DailyStats
+	52, // 35: tinyurl.v1.DailyStats.counters:type_name -> tinyurl.v1.DailyStats.CountersEntry
+	5,  // 36: tinyurl.v1.TinyURL.Shorten:input_type -> tinyurl.v1.ShortenRequest
+	11, // 37: tinyurl.v1.TinyURL.GetOriginal:input_type -> tinyurl.v1.GetOriginalRequest
+	13, // 38: tinyurl.v1.TinyURL.GetChallenge:input_type -> tinyurl.v1.GetChallengeRequest
+	15, // 39: tinyurl.v1.TinyURL.ReloadPolicies:input_type -> tinyurl.v1.ReloadPoliciesRequest
+	16, // 40: tinyurl.v1.TinyURL.GetPolicyStatus:input_type -> tinyurl.v1.GetPolicyStatusRequest
+	18, // 41: tinyurl.v1.TinyURL.ImportThreatList:input_type -> tinyurl.v1.ImportThreatListRequest
+	20, // 42: tinyurl.v1.TinyURL.ReportLink:input_type -> tinyurl.v1.ReportLinkRequest
+	23, // 43: tinyurl.v1.TinyURL.ListReports:input_type -> tinyurl.v1.ListReportsRequest
+	25, // 44: tinyurl.v1.TinyURL.ResolveReport:input_type -> tinyurl.v1.ResolveReportRequest
+	26, // 45: tinyurl.v1.TinyURL.DisableLink:input_type -> tinyurl.v1.DisableLinkRequest
+	28, // 46: tinyurl.v1.TinyURL.DeleteLink:input_type -> tinyurl.v1.DeleteLinkRequest
+	30, // 47: tinyurl.v1.TinyURL.BulkUpdateLinks:input_type -> tinyurl.v1.BulkUpdateLinksRequest
+	33, // 48: tinyurl.v1.TinyURL.GetLink:input_type -> tinyurl.v1.GetLinkRequest
+	38, // 49: tinyurl.v1.TinyURL.UpdateLink:input_type -> tinyurl.v1.UpdateLinkRequest
+	39, // 50: tinyurl.v1.TinyURL.ListBrokenLinks:input_type -> tinyurl.v1.ListBrokenLinksRequest
+	45, // 51: tinyurl.v1.TinyURL.GetLinkStats:input_type -> tinyurl.v1.GetLinkStatsRequest
+	41, // 52: tinyurl.v1.TinyURL.UnlockLink:input_type -> tinyurl.v1.UnlockLinkRequest
+	43, // 53: tinyurl.v1.TinyURL.PreviewLink:input_type -> tinyurl.v1.PreviewLinkRequest
+	10, // 54: tinyurl.v1.TinyURL.Shorten:output_type -> tinyurl.v1.ShortenResponse
+	12, // 55: tinyurl.v1.TinyURL.GetOriginal:output_type -> tinyurl.v1.GetOriginalResponse
+	14, // 56: tinyurl.v1.TinyURL.GetChallenge:output_type -> tinyurl.v1.GetChallengeResponse
+	17, // 57: tinyurl.v1.TinyURL.ReloadPolicies:output_type -> tinyurl.v1.PolicyStatus
+	17, // 58: tinyurl.v1.TinyURL.GetPolicyStatus:output_type -> tinyurl.v1.PolicyStatus
+	19, // 59: tinyurl.v1.TinyURL.ImportThreatList:output_type -> tinyurl.v1.ImportThreatListResponse
+	21, // 60: tinyurl.v1.TinyURL.ReportLink:output_type -> tinyurl.v1.ReportLinkResponse
+	24, // 61: tinyurl.v1.TinyURL.ListReports:output_type -> tinyurl.v1.ListReportsResponse
+	22, // 62: tinyurl.v1.TinyURL.ResolveReport:output_type -> tinyurl.v1.Report
+	27, // 63: tinyurl.v1.TinyURL.DisableLink:output_type -> tinyurl.v1.DisableLinkResponse
+	29, // 64: tinyurl.v1.TinyURL.DeleteLink:output_type -> tinyurl.v1.DeleteLinkResponse
+	32, // 65: tinyurl.v1.TinyURL.BulkUpdateLinks:output_type -> tinyurl.v1.BulkUpdateProgress
+	35, // 66: tinyurl.v1.TinyURL.GetLink:output_type -> tinyurl.v1.LinkInfo
+	35, // 67: tinyurl.v1.TinyURL.UpdateLink:output_type -> tinyurl.v1.LinkInfo
+	40, // 68: tinyurl.v1.TinyURL.ListBrokenLinks:output_type -> tinyurl.v1.ListBrokenLinksResponse
+	46, // 69: tinyurl.v1.TinyURL.GetLinkStats:output_type -> tinyurl.v1.LinkStats
+	42, // 70: tinyurl.v1.TinyURL.UnlockLink:output_type -> tinyurl.v1.UnlockLinkResponse
+	44, // 71: tinyurl.v1.TinyURL.PreviewLink:output_type -> tinyurl.v1.LinkPreview
+	54, // [54:72] is the sub-list for method output_type
+	36, // [36:54] is the sub-list for method input_type
+	36, // [36:36] is the sub-list for extension type_name
+	36, // [36:36] is the sub-list for extension extendee
+	0,  // [0:36] is the sub-list for field type_name
 }
 
 func init() { file_proto_tinyurl_v1_tinyurl_proto_init() }
@@ -297,13 +4001,14 @@ func file_proto_tinyurl_v1_tinyurl_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_tinyurl_v1_tinyurl_proto_rawDesc), len(file_proto_tinyurl_v1_tinyurl_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   4,
+			NumEnums:      5,
+			NumMessages:   48,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_tinyurl_v1_tinyurl_proto_goTypes,
 		DependencyIndexes: file_proto_tinyurl_v1_tinyurl_proto_depIdxs,
+		EnumInfos:         file_proto_tinyurl_v1_tinyurl_proto_enumTypes,
 		MessageInfos:      file_proto_tinyurl_v1_tinyurl_proto_msgTypes,
 	}.Build()
 	File_proto_tinyurl_v1_tinyurl_proto = out.File
@@ -19,8 +19,24 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	TinyURL_Shorten_FullMethodName     = "/tinyurl.v1.TinyURL/Shorten"
-	TinyURL_GetOriginal_FullMethodName = "/tinyurl.v1.TinyURL/GetOriginal"
+	TinyURL_Shorten_FullMethodName          = "/tinyurl.v1.TinyURL/Shorten"
+	TinyURL_GetOriginal_FullMethodName      = "/tinyurl.v1.TinyURL/GetOriginal"
+	TinyURL_GetChallenge_FullMethodName     = "/tinyurl.v1.TinyURL/GetChallenge"
+	TinyURL_ReloadPolicies_FullMethodName   = "/tinyurl.v1.TinyURL/ReloadPolicies"
+	TinyURL_GetPolicyStatus_FullMethodName  = "/tinyurl.v1.TinyURL/GetPolicyStatus"
+	TinyURL_ImportThreatList_FullMethodName = "/tinyurl.v1.TinyURL/ImportThreatList"
+	TinyURL_ReportLink_FullMethodName       = "/tinyurl.v1.TinyURL/ReportLink"
+	TinyURL_ListReports_FullMethodName      = "/tinyurl.v1.TinyURL/ListReports"
+	TinyURL_ResolveReport_FullMethodName    = "/tinyurl.v1.TinyURL/ResolveReport"
+	TinyURL_DisableLink_FullMethodName      = "/tinyurl.v1.TinyURL/DisableLink"
+	TinyURL_DeleteLink_FullMethodName       = "/tinyurl.v1.TinyURL/DeleteLink"
+	TinyURL_BulkUpdateLinks_FullMethodName  = "/tinyurl.v1.TinyURL/BulkUpdateLinks"
+	TinyURL_GetLink_FullMethodName          = "/tinyurl.v1.TinyURL/GetLink"
+	TinyURL_UpdateLink_FullMethodName       = "/tinyurl.v1.TinyURL/UpdateLink"
+	TinyURL_ListBrokenLinks_FullMethodName  = "/tinyurl.v1.TinyURL/ListBrokenLinks"
+	TinyURL_GetLinkStats_FullMethodName     = "/tinyurl.v1.TinyURL/GetLinkStats"
+	TinyURL_UnlockLink_FullMethodName       = "/tinyurl.v1.TinyURL/UnlockLink"
+	TinyURL_PreviewLink_FullMethodName      = "/tinyurl.v1.TinyURL/PreviewLink"
 )
 
 // TinyURLClient is the client API for TinyURL service.
@@ -33,6 +49,55 @@ type TinyURLClient interface {
 	// This is primarily for internal use by the Redirect handler,
 	// but exposed via API for completeness.
 	GetOriginal(ctx context.Context, in *GetOriginalRequest, opts ...grpc.CallOption) (*GetOriginalResponse, error)
+	// GetChallenge issues a signed proof-of-work challenge that anonymous
+	// callers must solve before calling Shorten.
+	GetChallenge(ctx context.Context, in *GetChallengeRequest, opts ...grpc.CallOption) (*GetChallengeResponse, error)
+	// ReloadPolicies re-reads the domain policy file. Admin only.
+	ReloadPolicies(ctx context.Context, in *ReloadPoliciesRequest, opts ...grpc.CallOption) (*PolicyStatus, error)
+	// GetPolicyStatus reports the currently loaded domain policy. Admin only.
+	GetPolicyStatus(ctx context.Context, in *GetPolicyStatusRequest, opts ...grpc.CallOption) (*PolicyStatus, error)
+	// ImportThreatList stores SHA-256 hash prefixes of malicious URL
+	// expressions used to screen links. Admin only.
+	ImportThreatList(ctx context.Context, in *ImportThreatListRequest, opts ...grpc.CallOption) (*ImportThreatListResponse, error)
+	// ReportLink lets visitors report an abusive link. Reports are queued
+	// for moderation.
+	ReportLink(ctx context.Context, in *ReportLinkRequest, opts ...grpc.CallOption) (*ReportLinkResponse, error)
+	// ListReports returns the moderation queue, newest first. Admin only.
+	ListReports(ctx context.Context, in *ListReportsRequest, opts ...grpc.CallOption) (*ListReportsResponse, error)
+	// ResolveReport closes a report, optionally disabling or deleting the
+	// reported link. Admin only.
+	ResolveReport(ctx context.Context, in *ResolveReportRequest, opts ...grpc.CallOption) (*Report, error)
+	// DisableLink stops a link from redirecting without deleting it. Admin only.
+	DisableLink(ctx context.Context, in *DisableLinkRequest, opts ...grpc.CallOption) (*DisableLinkResponse, error)
+	// DeleteLink removes a link. Admin only.
+	DeleteLink(ctx context.Context, in *DeleteLinkRequest, opts ...grpc.CallOption) (*DeleteLinkResponse, error)
+	// BulkUpdateLinks disables, deletes or re-points every link whose
+	// destination host matches one of the patterns, streaming progress after
+	// each batch. Admin only.
+	BulkUpdateLinks(ctx context.Context, in *BulkUpdateLinksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BulkUpdateProgress], error)
+	// GetLink returns a link with the result of its latest health check.
+	// Admins can see every link, other callers only their own.
+	GetLink(ctx context.Context, in *GetLinkRequest, opts ...grpc.CallOption) (*LinkInfo, error)
+	// UpdateLink changes the settings of an existing link. Only the fields
+	// named in update_mask are changed; over HTTP the mask defaults to the
+	// fields present in the body. Admins can update every link, other callers
+	// only their own.
+	UpdateLink(ctx context.Context, in *UpdateLinkRequest, opts ...grpc.CallOption) (*LinkInfo, error)
+	// ListBrokenLinks returns the links whose destination failed several
+	// health checks in a row. Admins see all of them, other callers only
+	// their own.
+	ListBrokenLinks(ctx context.Context, in *ListBrokenLinksRequest, opts ...grpc.CallOption) (*ListBrokenLinksResponse, error)
+	// GetLinkStats returns the daily click counts of a link, kept as long as
+	// the owner's plan retains analytics. Admins can see every link, other
+	// callers only their own.
+	GetLinkStats(ctx context.Context, in *GetLinkStatsRequest, opts ...grpc.CallOption) (*LinkStats, error)
+	// UnlockLink checks the password of a protected link and returns a
+	// short-lived token to pass as unlock_token to GetOriginal. Wrong
+	// passwords are limited per link and client IP.
+	UnlockLink(ctx context.Context, in *UnlockLinkRequest, opts ...grpc.CallOption) (*UnlockLinkResponse, error)
+	// PreviewLink describes where a link goes without following it. It needs
+	// no API key, but hides the destination of password protected links.
+	PreviewLink(ctx context.Context, in *PreviewLinkRequest, opts ...grpc.CallOption) (*LinkPreview, error)
 }
 
 type tinyURLClient struct {
@@ -63,6 +128,175 @@ func (c *tinyURLClient) GetOriginal(ctx context.Context, in *GetOriginalRequest,
 	return out, nil
 }
 
+func (c *tinyURLClient) GetChallenge(ctx context.Context, in *GetChallengeRequest, opts ...grpc.CallOption) (*GetChallengeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetChallengeResponse)
+	err := c.cc.Invoke(ctx, TinyURL_GetChallenge_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tinyURLClient) ReloadPolicies(ctx context.Context, in *ReloadPoliciesRequest, opts ...grpc.CallOption) (*PolicyStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PolicyStatus)
+	err := c.cc.Invoke(ctx, TinyURL_ReloadPolicies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tinyURLClient) GetPolicyStatus(ctx context.Context, in *GetPolicyStatusRequest, opts ...grpc.CallOption) (*PolicyStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PolicyStatus)
+	err := c.cc.Invoke(ctx, TinyURL_GetPolicyStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tinyURLClient) ImportThreatList(ctx context.Context, in *ImportThreatListRequest, opts ...grpc.CallOption) (*ImportThreatListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportThreatListResponse)
+	err := c.cc.Invoke(ctx, TinyURL_ImportThreatList_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tinyURLClient) ReportLink(ctx context.Context, in *ReportLinkRequest, opts ...grpc.CallOption) (*ReportLinkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReportLinkResponse)
+	err := c.cc.Invoke(ctx, TinyURL_ReportLink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tinyURLClient) ListReports(ctx context.Context, in *ListReportsRequest, opts ...grpc.CallOption) (*ListReportsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListReportsResponse)
+	err := c.cc.Invoke(ctx, TinyURL_ListReports_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tinyURLClient) ResolveReport(ctx context.Context, in *ResolveReportRequest, opts ...grpc.CallOption) (*Report, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Report)
+	err := c.cc.Invoke(ctx, TinyURL_ResolveReport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tinyURLClient) DisableLink(ctx context.Context, in *DisableLinkRequest, opts ...grpc.CallOption) (*DisableLinkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DisableLinkResponse)
+	err := c.cc.Invoke(ctx, TinyURL_DisableLink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tinyURLClient) DeleteLink(ctx context.Context, in *DeleteLinkRequest, opts ...grpc.CallOption) (*DeleteLinkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteLinkResponse)
+	err := c.cc.Invoke(ctx, TinyURL_DeleteLink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tinyURLClient) BulkUpdateLinks(ctx context.Context, in *BulkUpdateLinksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BulkUpdateProgress], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TinyURL_ServiceDesc.Streams[0], TinyURL_BulkUpdateLinks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[BulkUpdateLinksRequest, BulkUpdateProgress]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TinyURL_BulkUpdateLinksClient = grpc.ServerStreamingClient[BulkUpdateProgress]
+
+func (c *tinyURLClient) GetLink(ctx context.Context, in *GetLinkRequest, opts ...grpc.CallOption) (*LinkInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LinkInfo)
+	err := c.cc.Invoke(ctx, TinyURL_GetLink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tinyURLClient) UpdateLink(ctx context.Context, in *UpdateLinkRequest, opts ...grpc.CallOption) (*LinkInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LinkInfo)
+	err := c.cc.Invoke(ctx, TinyURL_UpdateLink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tinyURLClient) ListBrokenLinks(ctx context.Context, in *ListBrokenLinksRequest, opts ...grpc.CallOption) (*ListBrokenLinksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBrokenLinksResponse)
+	err := c.cc.Invoke(ctx, TinyURL_ListBrokenLinks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tinyURLClient) GetLinkStats(ctx context.Context, in *GetLinkStatsRequest, opts ...grpc.CallOption) (*LinkStats, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LinkStats)
+	err := c.cc.Invoke(ctx, TinyURL_GetLinkStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tinyURLClient) UnlockLink(ctx context.Context, in *UnlockLinkRequest, opts ...grpc.CallOption) (*UnlockLinkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnlockLinkResponse)
+	err := c.cc.Invoke(ctx, TinyURL_UnlockLink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tinyURLClient) PreviewLink(ctx context.Context, in *PreviewLinkRequest, opts ...grpc.CallOption) (*LinkPreview, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LinkPreview)
+	err := c.cc.Invoke(ctx, TinyURL_PreviewLink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TinyURLServer is the server API for TinyURL service.
 // All implementations must embed UnimplementedTinyURLServer
 // for forward compatibility.
@@ -73,6 +307,55 @@ type TinyURLServer interface {
 	// This is primarily for internal use by the Redirect handler,
 	// but exposed via API for completeness.
 	GetOriginal(context.Context, *GetOriginalRequest) (*GetOriginalResponse, error)
+	// GetChallenge issues a signed proof-of-work challenge that anonymous
+	// callers must solve before calling Shorten.
+	GetChallenge(context.Context, *GetChallengeRequest) (*GetChallengeResponse, error)
+	// ReloadPolicies re-reads the domain policy file. Admin only.
+	ReloadPolicies(context.Context, *ReloadPoliciesRequest) (*PolicyStatus, error)
+	// GetPolicyStatus reports the currently loaded domain policy. Admin only.
+	GetPolicyStatus(context.Context, *GetPolicyStatusRequest) (*PolicyStatus, error)
+	// ImportThreatList stores SHA-256 hash prefixes of malicious URL
+	// expressions used to screen links. Admin only.
+	ImportThreatList(context.Context, *ImportThreatListRequest) (*ImportThreatListResponse, error)
+	// ReportLink lets visitors report an abusive link. Reports are queued
+	// for moderation.
+	ReportLink(context.Context, *ReportLinkRequest) (*ReportLinkResponse, error)
+	// ListReports returns the moderation queue, newest first. Admin only.
+	ListReports(context.Context, *ListReportsRequest) (*ListReportsResponse, error)
+	// ResolveReport closes a report, optionally disabling or deleting the
+	// reported link. Admin only.
+	ResolveReport(context.Context, *ResolveReportRequest) (*Report, error)
+	// DisableLink stops a link from redirecting without deleting it. Admin only.
+	DisableLink(context.Context, *DisableLinkRequest) (*DisableLinkResponse, error)
+	// DeleteLink removes a link. Admin only.
+	DeleteLink(context.Context, *DeleteLinkRequest) (*DeleteLinkResponse, error)
+	// BulkUpdateLinks disables, deletes or re-points every link whose
+	// destination host matches one of the patterns, streaming progress after
+	// each batch. Admin only.
+	BulkUpdateLinks(*BulkUpdateLinksRequest, grpc.ServerStreamingServer[BulkUpdateProgress]) error
+	// GetLink returns a link with the result of its latest health check.
+	// Admins can see every link, other callers only their own.
+	GetLink(context.Context, *GetLinkRequest) (*LinkInfo, error)
+	// UpdateLink changes the settings of an existing link. Only the fields
+	// named in update_mask are changed; over HTTP the mask defaults to the
+	// fields present in the body. Admins can update every link, other callers
+	// only their own.
+	UpdateLink(context.Context, *UpdateLinkRequest) (*LinkInfo, error)
+	// ListBrokenLinks returns the links whose destination failed several
+	// health checks in a row. Admins see all of them, other callers only
+	// their own.
+	ListBrokenLinks(context.Context, *ListBrokenLinksRequest) (*ListBrokenLinksResponse, error)
+	// GetLinkStats returns the daily click counts of a link, kept as long as
+	// the owner's plan retains analytics. Admins can see every link, other
+	// callers only their own.
+	GetLinkStats(context.Context, *GetLinkStatsRequest) (*LinkStats, error)
+	// UnlockLink checks the password of a protected link and returns a
+	// short-lived token to pass as unlock_token to GetOriginal. Wrong
+	// passwords are limited per link and client IP.
+	UnlockLink(context.Context, *UnlockLinkRequest) (*UnlockLinkResponse, error)
+	// PreviewLink describes where a link goes without following it. It needs
+	// no API key, but hides the destination of password protected links.
+	PreviewLink(context.Context, *PreviewLinkRequest) (*LinkPreview, error)
 	mustEmbedUnimplementedTinyURLServer()
 }
 
@@ -89,6 +372,54 @@ func (UnimplementedTinyURLServer) Shorten(context.Context, *ShortenRequest) (*Sh
 func (UnimplementedTinyURLServer) GetOriginal(context.Context, *GetOriginalRequest) (*GetOriginalResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetOriginal not implemented")
 }
+func (UnimplementedTinyURLServer) GetChallenge(context.Context, *GetChallengeRequest) (*GetChallengeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetChallenge not implemented")
+}
+func (UnimplementedTinyURLServer) ReloadPolicies(context.Context, *ReloadPoliciesRequest) (*PolicyStatus, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReloadPolicies not implemented")
+}
+func (UnimplementedTinyURLServer) GetPolicyStatus(context.Context, *GetPolicyStatusRequest) (*PolicyStatus, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetPolicyStatus not implemented")
+}
+func (UnimplementedTinyURLServer) ImportThreatList(context.Context, *ImportThreatListRequest) (*ImportThreatListResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ImportThreatList not implemented")
+}
+func (UnimplementedTinyURLServer) ReportLink(context.Context, *ReportLinkRequest) (*ReportLinkResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReportLink not implemented")
+}
+func (UnimplementedTinyURLServer) ListReports(context.Context, *ListReportsRequest) (*ListReportsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListReports not implemented")
+}
+func (UnimplementedTinyURLServer) ResolveReport(context.Context, *ResolveReportRequest) (*Report, error) {
+	return nil, status.Error(codes.Unimplemented, "method ResolveReport not implemented")
+}
+func (UnimplementedTinyURLServer) DisableLink(context.Context, *DisableLinkRequest) (*DisableLinkResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DisableLink not implemented")
+}
+func (UnimplementedTinyURLServer) DeleteLink(context.Context, *DeleteLinkRequest) (*DeleteLinkResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteLink not implemented")
+}
+func (UnimplementedTinyURLServer) BulkUpdateLinks(*BulkUpdateLinksRequest, grpc.ServerStreamingServer[BulkUpdateProgress]) error {
+	return status.Error(codes.Unimplemented, "method BulkUpdateLinks not implemented")
+}
+func (UnimplementedTinyURLServer) GetLink(context.Context, *GetLinkRequest) (*LinkInfo, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetLink not implemented")
+}
+func (UnimplementedTinyURLServer) UpdateLink(context.Context, *UpdateLinkRequest) (*LinkInfo, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateLink not implemented")
+}
+func (UnimplementedTinyURLServer) ListBrokenLinks(context.Context, *ListBrokenLinksRequest) (*ListBrokenLinksResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListBrokenLinks not implemented")
+}
+func (UnimplementedTinyURLServer) GetLinkStats(context.Context, *GetLinkStatsRequest) (*LinkStats, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetLinkStats not implemented")
+}
+func (UnimplementedTinyURLServer) UnlockLink(context.Context, *UnlockLinkRequest) (*UnlockLinkResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UnlockLink not implemented")
+}
+func (UnimplementedTinyURLServer) PreviewLink(context.Context, *PreviewLinkRequest) (*LinkPreview, error) {
+	return nil, status.Error(codes.Unimplemented, "method PreviewLink not implemented")
+}
 func (UnimplementedTinyURLServer) mustEmbedUnimplementedTinyURLServer() {}
 func (UnimplementedTinyURLServer) testEmbeddedByValue()                 {}
 
@@ -146,6 +477,287 @@ func _TinyURL_GetOriginal_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TinyURL_GetChallenge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChallengeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinyURLServer).GetChallenge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TinyURL_GetChallenge_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinyURLServer).GetChallenge(ctx, req.(*GetChallengeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TinyURL_ReloadPolicies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReloadPoliciesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinyURLServer).ReloadPolicies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TinyURL_ReloadPolicies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinyURLServer).ReloadPolicies(ctx, req.(*ReloadPoliciesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TinyURL_GetPolicyStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPolicyStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinyURLServer).GetPolicyStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TinyURL_GetPolicyStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinyURLServer).GetPolicyStatus(ctx, req.(*GetPolicyStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TinyURL_ImportThreatList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportThreatListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinyURLServer).ImportThreatList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TinyURL_ImportThreatList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinyURLServer).ImportThreatList(ctx, req.(*ImportThreatListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TinyURL_ReportLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinyURLServer).ReportLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TinyURL_ReportLink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinyURLServer).ReportLink(ctx, req.(*ReportLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TinyURL_ListReports_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReportsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinyURLServer).ListReports(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TinyURL_ListReports_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinyURLServer).ListReports(ctx, req.(*ListReportsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TinyURL_ResolveReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinyURLServer).ResolveReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TinyURL_ResolveReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinyURLServer).ResolveReport(ctx, req.(*ResolveReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TinyURL_DisableLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisableLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinyURLServer).DisableLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TinyURL_DisableLink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinyURLServer).DisableLink(ctx, req.(*DisableLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TinyURL_DeleteLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinyURLServer).DeleteLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TinyURL_DeleteLink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinyURLServer).DeleteLink(ctx, req.(*DeleteLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TinyURL_BulkUpdateLinks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BulkUpdateLinksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TinyURLServer).BulkUpdateLinks(m, &grpc.GenericServerStream[BulkUpdateLinksRequest, BulkUpdateProgress]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TinyURL_BulkUpdateLinksServer = grpc.ServerStreamingServer[BulkUpdateProgress]
+
+func _TinyURL_GetLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinyURLServer).GetLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TinyURL_GetLink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinyURLServer).GetLink(ctx, req.(*GetLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TinyURL_UpdateLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinyURLServer).UpdateLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TinyURL_UpdateLink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinyURLServer).UpdateLink(ctx, req.(*UpdateLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TinyURL_ListBrokenLinks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBrokenLinksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinyURLServer).ListBrokenLinks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TinyURL_ListBrokenLinks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinyURLServer).ListBrokenLinks(ctx, req.(*ListBrokenLinksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TinyURL_GetLinkStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLinkStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinyURLServer).GetLinkStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TinyURL_GetLinkStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinyURLServer).GetLinkStats(ctx, req.(*GetLinkStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TinyURL_UnlockLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlockLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinyURLServer).UnlockLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TinyURL_UnlockLink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinyURLServer).UnlockLink(ctx, req.(*UnlockLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TinyURL_PreviewLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PreviewLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinyURLServer).PreviewLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TinyURL_PreviewLink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinyURLServer).PreviewLink(ctx, req.(*PreviewLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TinyURL_ServiceDesc is the grpc.ServiceDesc for TinyURL service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -161,7 +773,73 @@ var TinyURL_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetOriginal",
 			Handler:    _TinyURL_GetOriginal_Handler,
 		},
+		{
+			MethodName: "GetChallenge",
+			Handler:    _TinyURL_GetChallenge_Handler,
+		},
+		{
+			MethodName: "ReloadPolicies",
+			Handler:    _TinyURL_ReloadPolicies_Handler,
+		},
+		{
+			MethodName: "GetPolicyStatus",
+			Handler:    _TinyURL_GetPolicyStatus_Handler,
+		},
+		{
+			MethodName: "ImportThreatList",
+			Handler:    _TinyURL_ImportThreatList_Handler,
+		},
+		{
+			MethodName: "ReportLink",
+			Handler:    _TinyURL_ReportLink_Handler,
+		},
+		{
+			MethodName: "ListReports",
+			Handler:    _TinyURL_ListReports_Handler,
+		},
+		{
+			MethodName: "ResolveReport",
+			Handler:    _TinyURL_ResolveReport_Handler,
+		},
+		{
+			MethodName: "DisableLink",
+			Handler:    _TinyURL_DisableLink_Handler,
+		},
+		{
+			MethodName: "DeleteLink",
+			Handler:    _TinyURL_DeleteLink_Handler,
+		},
+		{
+			MethodName: "GetLink",
+			Handler:    _TinyURL_GetLink_Handler,
+		},
+		{
+			MethodName: "UpdateLink",
+			Handler:    _TinyURL_UpdateLink_Handler,
+		},
+		{
+			MethodName: "ListBrokenLinks",
+			Handler:    _TinyURL_ListBrokenLinks_Handler,
+		},
+		{
+			MethodName: "GetLinkStats",
+			Handler:    _TinyURL_GetLinkStats_Handler,
+		},
+		{
+			MethodName: "UnlockLink",
+			Handler:    _TinyURL_UnlockLink_Handler,
+		},
+		{
+			MethodName: "PreviewLink",
+			Handler:    _TinyURL_PreviewLink_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BulkUpdateLinks",
+			Handler:       _TinyURL_BulkUpdateLinks_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/tinyurl/v1/tinyurl.proto",
 }
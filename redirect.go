@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"tinyurl/internal/service"
+	pb "tinyurl/proto/tinyurl/v1"
+)
+
+// RedirectHandler serves GET /{shortCode}, resolving the code through the
+// gRPC service on behalf of the visitor.
+type RedirectHandler struct {
+	client pb.TinyURLClient
+}
+
+func (h *RedirectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// The code is the first path segment, anything after it may be passed
+	// through to the destination
+	shortCode, extraPath, hasPath := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if hasPath {
+		extraPath = "/" + extraPath
+	}
+	if shortCode == "" {
+		http.ServeFile(w, r, "index.html") // Fallback
+		return
+	}
+
+	ip := getRealIP(r)
+	if code, ok := previewCode(shortCode, extraPath); ok && r.Method != http.MethodPost {
+		h.preview(w, r, code, ip, "")
+		return
+	}
+
+	// The password form of protected links posts back to the short URL,
+	// other POSTs are redirected like any other request
+	if r.Method == http.MethodPost && isUnlockForm(w, r, shortCode) && h.unlock(w, r, shortCode, ip) {
+		return
+	}
+
+	// Call gRPC GetOriginal on behalf of the visitor, so the rate limiter
+	// sees the visitor's IP instead of ours
+	var header metadata.MD
+	callCtx := metadata.AppendToOutgoingContext(r.Context(), "x-forwarded-for", ip, service.RefererMetadata, r.Referer())
+	resp, err := h.client.GetOriginal(callCtx, &pb.GetOriginalRequest{
+		ShortCode:      shortCode,
+		Path:           extraPath,
+		Query:          r.URL.RawQuery,
+		UserAgent:      r.UserAgent(),
+		AcceptLanguage: r.Header.Get("Accept-Language"),
+		Variant:        cookieValue(r, variantCookieName(shortCode)),
+		UnlockToken:    cookieValue(r, unlockCookieName(shortCode)),
+		Preview:        isPreview(r),
+	}, grpc.Header(&header))
+	copyRateLimitHeaders(w, header)
+	if info := service.ErrorInfo(err); info != nil && info.Reason == service.ReasonPasswordRequired && !wantsJSON(r) {
+		passwordForm(w, r, shortCode, "", http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if resp.Variant != "" {
+		setVariantCookie(w, shortCode, resp.Variant)
+	}
+
+	// Bots and prefetches of links with a click limit don't learn the
+	// destination. Following the button is a visit of its own.
+	if resp.Limited && resp.LongUrl == "" {
+		w.Header().Set("Cache-Control", "no-store")
+		renderPage(w, http.StatusOK, "limited.html", map[string]string{
+			"Title":     "Open this link",
+			"ShortCode": shortCode,
+		})
+		return
+	}
+
+	// Flagged destinations get a warning page instead of a redirect
+	if resp.Flagged {
+		w.Header().Set("Cache-Control", "no-store")
+		renderPage(w, http.StatusOK, "warning.html", map[string]string{
+			"Title":     "Dangerous link ahead",
+			"Warning":   resp.Warning,
+			"LongURL":   resp.LongUrl,
+			"ShortCode": shortCode,
+		})
+		return
+	}
+
+	// Links of untrusted destinations always show where they go first
+	if resp.Interstitial {
+		h.preview(w, r, shortCode, ip, resp.LongUrl)
+		return
+	}
+
+	redirect(w, r, resp)
+}
+
+// VariantCookieMaxAge is how long visitors keep the A/B variant they were
+// assigned.
+var VariantCookieMaxAge = 30 * 24 * time.Hour
+
+func variantCookieName(shortCode string) string {
+	return "variant_" + shortCode
+}
+
+func setVariantCookie(w http.ResponseWriter, shortCode, variant string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     variantCookieName(shortCode),
+		Value:    variant,
+		Path:     "/" + shortCode,
+		MaxAge:   int(VariantCookieMaxAge.Seconds()),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// isPreview tells requests that only look at a link, like HEAD requests of
+// link unfurlers and browser prefetches, from visits.
+func isPreview(r *http.Request) bool {
+	if r.Method == http.MethodHead {
+		return true
+	}
+	purpose := r.Header.Get("Sec-Purpose") + r.Header.Get("Purpose") + r.Header.Get("X-Purpose")
+	return strings.Contains(strings.ToLower(purpose), "prefetch") || strings.Contains(strings.ToLower(purpose), "preview")
+}
+
+// PermanentRedirectMaxAge caps how long browsers may cache 301 and 308
+// redirects, so disabling or re-pointing a link takes effect eventually.
+var PermanentRedirectMaxAge = 24 * time.Hour
+
+// InterstitialDelay is how long the meta refresh and JavaScript pages are
+// shown, in seconds.
+const InterstitialDelay = 3
+
+var redirectCodes = map[pb.RedirectType]int{
+	pb.RedirectType_REDIRECT_TYPE_301: http.StatusMovedPermanently,
+	pb.RedirectType_REDIRECT_TYPE_302: http.StatusFound,
+	pb.RedirectType_REDIRECT_TYPE_303: http.StatusSeeOther,
+	pb.RedirectType_REDIRECT_TYPE_307: http.StatusTemporaryRedirect,
+	pb.RedirectType_REDIRECT_TYPE_308: http.StatusPermanentRedirect,
+}
+
+// redirect sends the visitor to the destination the way the link asks
+// for. Only permanent redirects may be cached, everything else has to come
+// back to us on every click.
+func redirect(w http.ResponseWriter, r *http.Request, resp *pb.GetOriginalResponse) {
+	redirectType := resp.RedirectType
+	if resp.Fallback {
+		// The destination is only down for now
+		redirectType = pb.RedirectType_REDIRECT_TYPE_302
+	}
+
+	switch redirectType {
+	case pb.RedirectType_REDIRECT_TYPE_301, pb.RedirectType_REDIRECT_TYPE_308:
+		// A cached redirect would outlive the unlock or the last click
+		if resp.Protected || resp.Limited {
+			w.Header().Set("Cache-Control", "no-store")
+			break
+		}
+		maxAge := PermanentRedirectMaxAge
+		if resp.ExpiresAt > 0 {
+			maxAge = min(maxAge, time.Until(time.Unix(resp.ExpiresAt, 0)))
+		}
+		// Shared caches can't tell visitors of a targeted link apart
+		scope := "public"
+		if resp.Targeted {
+			scope = "private"
+		}
+		w.Header().Set("Cache-Control", fmt.Sprintf("%s, max-age=%d", scope, max(int(maxAge.Seconds()), 0)))
+	case pb.RedirectType_REDIRECT_TYPE_META_REFRESH, pb.RedirectType_REDIRECT_TYPE_JAVASCRIPT:
+		w.Header().Set("Cache-Control", "no-store")
+		renderPage(w, http.StatusOK, "interstitial.html", map[string]any{
+			"Title":   "Redirecting",
+			"LongURL": resp.LongUrl,
+			"Delay":   InterstitialDelay,
+			"Meta":    redirectType == pb.RedirectType_REDIRECT_TYPE_META_REFRESH,
+		})
+		return
+	default:
+		w.Header().Set("Cache-Control", "no-store")
+	}
+
+	code, ok := redirectCodes[redirectType]
+	if !ok {
+		code = http.StatusSeeOther
+	}
+	http.Redirect(w, r, resp.LongUrl, code)
+}
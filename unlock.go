@@ -0,0 +1,141 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"tinyurl/internal/service"
+	pb "tinyurl/proto/tinyurl/v1"
+)
+
+// CSRFTokenMaxAge is how long a password form can be left open before
+// submitting it.
+var CSRFTokenMaxAge = time.Hour
+
+func unlockCookieName(shortCode string) string {
+	return "unlock_" + shortCode
+}
+
+func csrfCookieName(shortCode string) string {
+	return "csrf_" + shortCode
+}
+
+// secureCookies keeps cookies off plain HTTP once the service runs on HTTPS.
+func secureCookies() bool {
+	return strings.HasPrefix(ServerURL, "https://")
+}
+
+func cookieValue(r *http.Request, name string) string {
+	c, err := r.Cookie(name)
+	if err != nil {
+		return ""
+	}
+	return c.Value
+}
+
+// isUnlockForm tells the password form from other POSTs to a short URL,
+// like those of 307 and 308 redirects. Only visitors who were shown the
+// form have its CSRF cookie, so nobody else's body is read.
+func isUnlockForm(w http.ResponseWriter, r *http.Request, shortCode string) bool {
+	if cookieValue(r, csrfCookieName(shortCode)) == "" {
+		return false
+	}
+	if ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); ct != "application/x-www-form-urlencoded" {
+		return false
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, 4096)
+	if err := r.ParseForm(); err != nil {
+		return false
+	}
+	return r.PostForm.Has("csrf_token") && r.PostForm.Has("password")
+}
+
+// unlock handles the password form of a protected link. The CSRF token is
+// a double-submit cookie, which other sites can neither read nor set. On
+// success the unlock token is kept in a cookie and the visitor is sent back
+// to the short URL with GET. It returns false for links that aren't
+// password protected (anymore), which are redirected instead.
+func (h *RedirectHandler) unlock(w http.ResponseWriter, r *http.Request, shortCode, ip string) bool {
+	cookie := cookieValue(r, csrfCookieName(shortCode))
+	form := r.PostForm.Get("csrf_token")
+	if subtle.ConstantTimeCompare([]byte(cookie), []byte(form)) != 1 {
+		passwordForm(w, r, shortCode, "Your session expired. Please try again.", http.StatusForbidden)
+		return true
+	}
+
+	var header metadata.MD
+	// The form posts the Referer the visitor came to the link with
+	callCtx := metadata.AppendToOutgoingContext(r.Context(), "x-forwarded-for", ip, service.RefererMetadata, r.PostForm.Get("referrer"))
+	resp, err := h.client.UnlockLink(callCtx, &pb.UnlockLinkRequest{
+		ShortCode: shortCode,
+		Password:  r.PostForm.Get("password"),
+	}, grpc.Header(&header))
+	copyRateLimitHeaders(w, header)
+	switch status.Code(err) {
+	case codes.OK:
+	case codes.FailedPrecondition:
+		return false
+	case codes.PermissionDenied, codes.ResourceExhausted, codes.InvalidArgument:
+		passwordForm(w, r, shortCode, status.Convert(err).Message(), httpStatus(err))
+		return true
+	default:
+		writeError(w, r, err)
+		return true
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     unlockCookieName(shortCode),
+		Value:    resp.Token,
+		Path:     "/" + shortCode,
+		Expires:  time.Unix(resp.ExpiresAt, 0),
+		HttpOnly: true,
+		Secure:   secureCookies(),
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, r.URL.RequestURI(), http.StatusSeeOther)
+	return true
+}
+
+// passwordForm renders the password form with a CSRF token, reusing the
+// visitor's current one so several open tabs keep working. The form keeps
+// the referrer the visitor came with, for links limited to some referrers.
+func passwordForm(w http.ResponseWriter, r *http.Request, shortCode, message string, code int) {
+	referrer := r.Referer()
+	if r.Method == http.MethodPost {
+		referrer = r.PostForm.Get("referrer")
+	}
+
+	token := cookieValue(r, csrfCookieName(shortCode))
+	if len(token) != 32 {
+		b := make([]byte, 16)
+		rand.Read(b)
+		token = hex.EncodeToString(b)
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     csrfCookieName(shortCode),
+		Value:    token,
+		Path:     "/" + shortCode,
+		MaxAge:   int(CSRFTokenMaxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   secureCookies(),
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	w.Header().Set("Cache-Control", "no-store")
+	renderPage(w, code, "password.html", map[string]string{
+		"Title":     "Password required",
+		"CSRFToken": token,
+		"Referrer":  referrer,
+		"Error":     message,
+	})
+}